@@ -2,6 +2,9 @@ package main
 
 import (
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -12,6 +15,9 @@ import (
 	"github.com/DarkieSouls/listto/internal/ddb"
 )
 
+// shutdownTimeout is how long commands in progress get to finish when the bot is stopped.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	config, err := config.NewConfig()
 	if err != nil {
@@ -24,11 +30,21 @@ func main() {
 
 	ddbConn := dynamodb.New(sess)
 
-	ddb := ddb.New(ddbConn)
+	ddb := ddb.New(ddbConn, config.StorageRetries)
 
 	bot := bot.New(config, ddb)
 
-	bot.Start()
+	if err := bot.Start(); err != nil {
+		err.LogError()
+		os.Exit(-1)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
 
-	<-make(chan struct{})
+	if err := bot.Stop(shutdownTimeout); err != nil {
+		err.LogError()
+		os.Exit(-1)
+	}
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/DarkieSouls/listto/internal/listtoErr"
@@ -9,8 +10,10 @@ import (
 
 // Config contains the configuration of the bot.
 type Config struct {
-	Token  string
-	Prefix string
+	Token          string
+	Prefix         string
+	AllowBots      bool
+	StorageRetries int
 }
 
 // NewConfig generates a new configuration based on current envvars.
@@ -32,9 +35,24 @@ func NewConfig() (c *Config, lisErr *listtoErr.ListtoError) {
 		prefix = "^"
 	}
 
+	// Other bots are ignored unless allowed, so two bots can't set each other off.
+	allowBots := strings.EqualFold(strings.TrimSpace(os.Getenv("LISTTO_ALLOW_BOTS")), "true")
+
+	storageRetries := 3
+	if retries := strings.TrimSpace(os.Getenv("LISTTO_STORAGE_RETRIES")); retries != "" {
+		var err error
+		storageRetries, err = strconv.Atoi(retries)
+		if err != nil || storageRetries < 0 {
+			lisErr = listtoErr.InvalidEnvvar("storage retries")
+			return
+		}
+	}
+
 	c = new(Config)
 	c.Token = token
 	c.Prefix = prefix
+	c.AllowBots = allowBots
+	c.StorageRetries = storageRetries
 
 	return
 }
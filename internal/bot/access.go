@@ -0,0 +1,230 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+)
+
+// accessEntry is a user or role allowed to use a list, with the name they currently go by.
+type accessEntry struct {
+	id    string
+	name  string
+	role  bool
+	stale bool
+}
+
+// accessList shows who can use a list, or prunes users and roles that no longer exist when given prune.
+func (b *bot) accessList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if lis.Type == lists.PublicList {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is public, so everybody can use it", list),
+			Color:       blue,
+		}
+	}
+
+	entries, err := b.resolveAccess(lis)
+	if err != nil {
+		fmt.Println("failed to look up list access", err)
+		return failMsg()
+	}
+
+	switch strings.ToLower(arg) {
+	case "":
+		return accessEmbed(lis, entries)
+	case "prune":
+		return b.pruneAccess(c, lis, entries)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: "I can show who can use a list, or prune anybody that no longer exists",
+		Color:       yellow,
+	}
+}
+
+// pruneAccess removes the users and roles that no longer exist from a list.
+func (b *bot) pruneAccess(c *caller, lis *lists.ListtoList, entries []accessEntry) *discordgo.MessageEmbed {
+	if msg := b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	var stale []string
+	for _, e := range entries {
+		if e.stale {
+			stale = append(stale, e.id)
+		}
+	}
+
+	if len(stale) == 0 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Everybody allowed on %s still exists", lis.Name),
+			Color:       green,
+		}
+	}
+
+	var removed []string
+	if lis.Type == lists.PersonalList {
+		for _, s := range stale {
+			if lis.Unshare(s) {
+				removed = append(removed, s)
+			}
+		}
+	} else {
+		removed = lis.RemoveAccess(stale)
+	}
+
+	if len(removed) == 0 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't remove anybody from %s, as it needs to keep at least one allowed user", lis.Name),
+			Color:       yellow,
+		}
+	}
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't update the permissions for %s", lis.Name),
+			Color:       red,
+		})
+	}
+
+	desc := fmt.Sprintf("I have removed %d users and roles that no longer exist from %s", len(removed), lis.Name)
+	if kept := len(stale) - len(removed); kept > 0 {
+		desc = fmt.Sprintf("%s. I kept %d, as the list needs at least one allowed user", desc, kept)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: desc,
+		Color:       green,
+	}
+}
+
+// accessEmbed shows the users and roles allowed on a list, marking any that no longer exist.
+func accessEmbed(lis *lists.ListtoList, entries []accessEntry) *discordgo.MessageEmbed {
+	var users, roles string
+	var stale int
+	for _, e := range entries {
+		name := e.name
+		if e.stale {
+			name = fmt.Sprintf("~~%s~~ (gone)", e.id)
+			stale++
+		}
+
+		if e.role {
+			roles = fmt.Sprintf("%s\n%s", roles, name)
+		} else {
+			users = fmt.Sprintf("%s\n%s", users, name)
+		}
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	if users != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Users", Value: users, Inline: true})
+	}
+	if roles != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Roles", Value: roles, Inline: true})
+	}
+	if len(lis.Viewers) != 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Read only", Value: fmt.Sprintf("%d of these can only read the list", len(lis.Viewers))})
+	}
+
+	desc := fmt.Sprintf("These can use %s", lis.Name)
+	if lis.Type == lists.RestrictedList {
+		desc = fmt.Sprintf("Everybody can read %s, but only these can change it", lis.Name)
+	}
+	if stale > 0 {
+		desc = fmt.Sprintf("%s. %d no longer exist, and can be removed with prune", desc, stale)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: desc,
+		Color:       green,
+		Fields:      fields,
+	}
+}
+
+// resolveAccess looks up the names of the users and roles allowed on a list.
+// Roles are looked up on the list's server, and users must still be members of it.
+// Personal lists aren't on a server, so their users only need to exist.
+// The state cache is checked first, so Discord is only asked about those that aren't cached.
+func (b *bot) resolveAccess(lis *lists.ListtoList) ([]accessEntry, error) {
+	personal := lis.Type == lists.PersonalList
+
+	roleNames := make(map[string]string)
+	if !personal {
+		var roles []*discordgo.Role
+		if guild, err := b.Dgo.State.Guild(lis.Guild); err == nil {
+			roles = guild.Roles
+		} else {
+			roles, err = b.Dgo.GuildRoles(lis.Guild)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, r := range roles {
+			roleNames[r.ID] = r.Name
+		}
+	}
+
+	entries := make([]accessEntry, 0, len(lis.Access))
+	for _, id := range lis.Access {
+		if name, ok := roleNames[id]; ok {
+			entries = append(entries, accessEntry{id: id, name: "@" + name, role: true})
+			continue
+		}
+
+		var user *discordgo.User
+		if personal {
+			u, err := b.Dgo.User(id)
+			if err != nil && !notFound(err) {
+				return nil, err
+			}
+			user = u
+		} else {
+			member, err := b.Dgo.State.Member(lis.Guild, id)
+			if err != nil {
+				member, err = b.Dgo.GuildMember(lis.Guild, id)
+			}
+			if err != nil && !notFound(err) {
+				return nil, err
+			}
+			if err == nil {
+				user = member.User
+			}
+		}
+
+		if user == nil {
+			entries = append(entries, accessEntry{id: id, stale: true})
+			continue
+		}
+
+		entries = append(entries, accessEntry{id: id, name: user.Username})
+	}
+
+	return entries, nil
+}
+
+// mentions formats user and role IDs so Discord shows their names, using the cached roles for the server.
+func (b *bot) mentions(guild string, ids []string) string {
+	formatted := make([]string, len(ids))
+	for i, id := range ids {
+		formatted[i] = fmt.Sprintf("<@%s>", id)
+		if _, err := b.Dgo.State.Role(guild, id); err == nil {
+			formatted[i] = fmt.Sprintf("<@&%s>", id)
+		}
+	}
+
+	return strings.Join(formatted, ", ")
+}
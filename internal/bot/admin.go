@@ -0,0 +1,226 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// admin changes the server's admin settings, or shows them if no setting is given.
+// Only members with Manage Server can use it.
+func (b *bot) admin(c *caller, setting, arg, role string) *discordgo.MessageEmbed {
+	if c.dm {
+		return &discordgo.MessageEmbed{
+			Description: "Admin settings can only be changed on a server",
+			Color:       yellow,
+		}
+	}
+
+	if !b.hasPermission(c, discordgo.PermissionManageServer) {
+		return &discordgo.MessageEmbed{
+			Description: "You need the Manage Server permission to change admin settings",
+			Color:       yellow,
+		}
+	}
+
+	settings, err := b.DDB.GetSettings(c.ctx, c.guild)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	switch setting {
+	case "":
+		adminRole := "none"
+		if settings.AdminRole != "" {
+			adminRole = fmt.Sprintf("<@&%s>", settings.AdminRole)
+		}
+
+		return &discordgo.MessageEmbed{
+			Description: "Here are the admin settings for this server",
+			Color:       blue,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "role", Value: adminRole, Inline: true},
+				{Name: "publicdelete", Value: onOff(settings.PublicDelete), Inline: true},
+			},
+		}
+	case "role":
+		if role == "" && arg != "none" {
+			return &discordgo.MessageEmbed{
+				Description: "You need to mention the role that can manage every list, or none to remove it",
+				Color:       yellow,
+			}
+		}
+		settings.AdminRole = role
+	case "publicdelete":
+		on, ok := parseOnOff(arg)
+		if !ok {
+			return &discordgo.MessageEmbed{
+				Description: "Settings can only be turned on or off!",
+				Color:       yellow,
+			}
+		}
+		settings.PublicDelete = on
+	default:
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I don't know the admin setting %s. Try %shelp admin for more", setting, b.Config.Prefix),
+			Color:       yellow,
+		}
+	}
+
+	if err := b.DDB.PutSettings(c.ctx, settings); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: "I couldn't update the admin settings",
+			Color:       red,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have updated %s", setting),
+		Color:       green,
+	}
+}
+
+// hasPermission returns if the caller has a Discord permission in the channel they are using.
+func (b *bot) hasPermission(c *caller, permission int) bool {
+	if c.dm {
+		return false
+	}
+
+	perms, err := b.Dgo.UserChannelPermissions(c.user, c.channel)
+	if err != nil {
+		fmt.Println("failed to get permissions", err)
+		return false
+	}
+
+	return perms&permission != 0
+}
+
+// isAdmin returns if the caller can manage every list on the server,
+// either through the Manage Server permission or the server's admin role.
+func (b *bot) isAdmin(c *caller) (bool, *listtoErr.ListtoError) {
+	if c.admin != nil {
+		return *c.admin, nil
+	}
+
+	admin := b.hasPermission(c, discordgo.PermissionManageServer)
+	if !admin && !c.dm {
+		settings, err := b.DDB.GetSettings(c.ctx, c.guild)
+		if err != nil {
+			return false, err
+		}
+
+		for _, r := range c.roles {
+			if r == settings.AdminRole {
+				admin = true
+				break
+			}
+		}
+	}
+
+	c.admin = &admin
+	return admin, nil
+}
+
+// canAccess returns if the caller can use a list, which server admins can do for any list on their server.
+func (b *bot) canAccess(c *caller, lis *lists.ListtoList) (bool, *listtoErr.ListtoError) {
+	if lis.CanAccess(c.user, c.roles) {
+		return true, nil
+	}
+
+	if lis.Guild != c.guild {
+		return false, nil
+	}
+
+	return b.isAdmin(c)
+}
+
+// canEdit returns if the caller can change a list, which server admins can do for any list on their server.
+// Nobody can change a locked list, not even admins, until it is unlocked.
+func (b *bot) canEdit(c *caller, lis *lists.ListtoList) (bool, *listtoErr.ListtoError) {
+	if lis.Locked {
+		return false, nil
+	}
+
+	if lis.CanEdit(c.user, c.roles) {
+		return true, nil
+	}
+
+	if lis.Guild != c.guild {
+		return false, nil
+	}
+
+	return b.isAdmin(c)
+}
+
+// canDelete returns if the caller can delete a list they can change.
+// Public lists need the Manage Messages permission, unless the server allows anybody to delete them.
+func (b *bot) canDelete(c *caller, lis *lists.ListtoList) (bool, *listtoErr.ListtoError) {
+	if lis.Type != lists.PublicList || b.hasPermission(c, discordgo.PermissionManageMessages) {
+		return true, nil
+	}
+
+	admin, err := b.isAdmin(c)
+	if err != nil || admin {
+		return admin, err
+	}
+
+	settings, err := b.DDB.GetSettings(c.ctx, c.guild)
+	if err != nil {
+		return false, err
+	}
+
+	return settings.PublicDelete, nil
+}
+
+// canLock returns if the caller can lock or unlock a list.
+// As anybody can change a public list, locking one needs the Manage Messages permission.
+func (b *bot) canLock(c *caller, lis *lists.ListtoList) (bool, *listtoErr.ListtoError) {
+	if lis.Type != lists.PublicList && lis.CanManage(c.user, c.roles) {
+		return true, nil
+	}
+
+	if lis.Guild != c.guild {
+		return false, nil
+	}
+
+	if b.hasPermission(c, discordgo.PermissionManageMessages) {
+		return true, nil
+	}
+
+	return b.isAdmin(c)
+}
+
+// checkAccess returns a message if the caller can't use a list.
+func (b *bot) checkAccess(c *caller, lis *lists.ListtoList) *discordgo.MessageEmbed {
+	ok, err := b.canAccess(c, lis)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if !ok {
+		return noPerms(lis.Name)
+	}
+
+	return nil
+}
+
+// checkEdit returns a message if the caller can't change a list.
+func (b *bot) checkEdit(c *caller, lis *lists.ListtoList) *discordgo.MessageEmbed {
+	ok, err := b.canEdit(c, lis)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if !ok {
+		return readOnly(lis)
+	}
+
+	return nil
+}
@@ -1,8 +1,11 @@
 package bot
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 
@@ -12,59 +15,134 @@ import (
 )
 
 type DDB interface {
-	GetList(string, string) (*lists.ListtoList, *listtoErr.ListtoError)
-	GetAllLists(string, string) ([]*lists.ListtoList, *listtoErr.ListtoError)
-	PutList(interface{}) *listtoErr.ListtoError
-	DeleteList(string, string, string) *listtoErr.ListtoError
+	GetList(context.Context, string, string) (*lists.ListtoList, *listtoErr.ListtoError)
+	GetAllLists(context.Context, string, string) ([]*lists.ListtoList, *listtoErr.ListtoError)
+	PutList(context.Context, interface{}) *listtoErr.ListtoError
+	DeleteList(context.Context, string, string, string) *listtoErr.ListtoError
+	MoveList(context.Context, *lists.ListtoList, string) *listtoErr.ListtoError
+	CountLists(context.Context, string) (int, *listtoErr.ListtoError)
+	GetTemplate(context.Context, string, string) (*lists.Template, *listtoErr.ListtoError)
+	GetAllTemplates(context.Context, string, string) ([]*lists.Template, *listtoErr.ListtoError)
+	PutTemplate(context.Context, *lists.Template) *listtoErr.ListtoError
+	DeleteTemplate(context.Context, string, string) *listtoErr.ListtoError
+	GetSchedule(context.Context, string, string) (*lists.Schedule, *listtoErr.ListtoError)
+	GetAllSchedules(context.Context) ([]*lists.Schedule, *listtoErr.ListtoError)
+	PutSchedule(context.Context, *lists.Schedule) *listtoErr.ListtoError
+	DeleteSchedule(context.Context, string, string) *listtoErr.ListtoError
+	GetPoll(context.Context, string, string) (*lists.Poll, *listtoErr.ListtoError)
+	GetAllPolls(context.Context) ([]*lists.Poll, *listtoErr.ListtoError)
+	PutPoll(context.Context, *lists.Poll) *listtoErr.ListtoError
+	DeletePoll(context.Context, string, string) *listtoErr.ListtoError
+	GetSettings(context.Context, string) (*lists.Settings, *listtoErr.ListtoError)
+	PutSettings(context.Context, *lists.Settings) *listtoErr.ListtoError
+	GetShare(context.Context, string) (*lists.Share, *listtoErr.ListtoError)
+	PutShare(context.Context, *lists.Share) *listtoErr.ListtoError
+	DeleteShare(context.Context, string) *listtoErr.ListtoError
 }
 
+// commandTimeout is how long a command has to finish, before any storage calls it is making are given up.
+const commandTimeout = 10 * time.Second
+
 // bot holds all the info that needs to be passed around the bot.
 type bot struct {
 	Dgo    *discordgo.Session
 	BotID  string
 	Config *config.Config
 	DDB    DDB
+
+	userLimit  *limiter
+	guildLimit *limiter
+
+	schedules *dueTimes
+	polls     *dueTimes
+
+	// Stopping is guarded by mu, so that nothing new starts once Stop has been called.
+	mu       sync.Mutex
+	stopping bool
+	stopped  chan struct{}
+	inFlight sync.WaitGroup
+}
+
+// caller holds who sent a command, and where they sent it from.
+// For DMs the guild is the user's ID, so that personal lists are found under the user.
+// Whether the caller is a server admin is only looked up when it's first needed.
+// ctx carries the deadline for the command, and is passed to every storage call it makes.
+// found holds a list already looked up for the command, so it isn't read from storage again.
+type caller struct {
+	ctx      context.Context
+	guild    string
+	channel  string
+	category string
+	user     string
+	roles    []string
+	dm       bool
+	admin    *bool
+	found    *lists.ListtoList
 }
 
 // New creates a new bot instance.
 func New(conf *config.Config, ddb DDB) *bot {
 	return &bot{
-		Config: conf,
-		DDB:    ddb,
+		Config:     conf,
+		DDB:        ddb,
+		userLimit:  newLimiter(userRate, userBurst),
+		guildLimit: newLimiter(guildRate, guildBurst),
+		schedules:  newDueTimes(),
+		polls:      newDueTimes(),
+		stopped:    make(chan struct{}),
 	}
 }
 
 // Start the bot listener.
-func (b *bot) Start() {
+func (b *bot) Start() (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("Start")
+		}
+	}()
+
 	dgo, err := discordgo.New("Bot " + b.Config.Token)
 	if err != nil {
-		fmt.Println("could not create session", err)
-		return
+		return listtoErr.ConvertError(fmt.Errorf("could not create session: %w", err))
 	}
 	b.Dgo = dgo
 
+	// Recent messages are cached, so reactions can be checked without asking Discord for the message every time.
+	b.Dgo.State.MaxMessageCount = reactionCacheSize
+
 	u, err := b.Dgo.User("@me")
 	if err != nil {
-		fmt.Println("could not get bot user", err)
+		return listtoErr.ConvertError(fmt.Errorf("could not get bot user: %w", err))
 	}
 
 	b.BotID = u.ID
 
 	b.Dgo.AddHandler(b.messageHandler())
+	b.Dgo.AddHandler(b.reactionHandler())
 
 	if err := b.Dgo.Open(); err != nil {
-		fmt.Println("could not open session", err)
-		return
+		return listtoErr.ConvertError(fmt.Errorf("could not open session: %w", err))
 	}
 
 	b.Dgo.UpdateStatus(0, fmt.Sprintf("with %shelp", b.Config.Prefix))
 
+	// Schedules and polls are stored, so any that came due while the bot was down are run once it's back.
+	go b.runDue(b.schedules, b.scheduleTimes, b.checkSchedule)
+	go b.runDue(b.polls, b.pollTimes, b.checkPoll)
+
 	fmt.Println("The bot has awoken...")
+
+	return nil
 }
 
 // messageHandler returns a handlerfunc for messages.
 func (b *bot) messageHandler() func(s *discordgo.Session, m *discordgo.MessageCreate) {
 	return func(s *discordgo.Session, m *discordgo.MessageCreate) {
+		if !b.begin() {
+			return
+		}
+		defer b.inFlight.Done()
+
 		var list, arg string
 		var roles []string
 		channel := m.ChannelID
@@ -79,6 +157,14 @@ func (b *bot) messageHandler() func(s *discordgo.Session, m *discordgo.MessageCr
 			return
 		}
 
+		if m.Author.Bot && !b.Config.AllowBots {
+			return
+		}
+
+		if b.rateLimited(s, m) {
+			return
+		}
+
 		channelS, err := s.Channel(channel)
 		if err != nil {
 			fmt.Println("Failed to get channel", err)
@@ -92,6 +178,19 @@ func (b *bot) messageHandler() func(s *discordgo.Session, m *discordgo.MessageCr
 			guild = m.GuildID
 		}
 
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+
+		c := &caller{
+			ctx:      ctx,
+			guild:    guild,
+			channel:  channel,
+			category: channelS.ParentID,
+			user:     user,
+			roles:    roles,
+			dm:       dm,
+		}
+
 		message := strings.Split(strings.TrimPrefix(m.Content, b.Config.Prefix), " ")
 		if len(message) == 0 {
 			return
@@ -110,30 +209,160 @@ func (b *bot) messageHandler() func(s *discordgo.Session, m *discordgo.MessageCr
 		var resp *discordgo.MessageEmbed
 
 		command := strings.ToLower(message[0])
+		if defaultCommands[command] {
+			var msg *discordgo.MessageEmbed
+			if list, arg, msg = b.withDefault(c, list, arg); msg != nil {
+				b.reply(s, channel, msg)
+				return
+			}
+		}
+
 		switch command {
 		case "add", "a":
-			resp = b.addToList(guild, list, arg, user, roles)
+			resp = b.addToList(c, list, arg)
+		case "addchild", "ac":
+			resp = b.addChildToList(c, list, arg)
 		case "clear", "cl":
-			resp = b.clearList(guild, list, user, roles)
+			resp = b.clearList(c, list)
 		case "create", "c":
+			bind, rest, msg := parseBinding(c, arg)
+			if msg != nil {
+				resp = msg
+				break
+			}
+
+			if strings.HasPrefix(rest, "from:") {
+				resp = b.createFromTemplate(c, list, strings.TrimPrefix(rest, "from:"), bind)
+				break
+			}
+
 			var access []string
 			if dm {
 				access = []string{user}
 			}
-			resp = b.createList(guild, list, dm, access)
+			resp = b.createList(c, list, lists.PublicList, access, bind)
+		case "limits":
+			resp = b.limits(c, strings.ToLower(list), arg)
+		case "admin":
+			var role string
+			if len(m.MentionRoles) != 0 {
+				role = m.MentionRoles[0]
+			}
+			resp = b.admin(c, strings.ToLower(list), arg, role)
+		case "default":
+			resp = b.defaultList(c, list)
+		case "makeprivate":
+			var access []string
+			access = append(access, m.MentionRoles...)
+			for _, u := range m.Mentions {
+				access = append(access, u.ID)
+			}
+			resp = b.makePrivate(c, list, access)
+		case "makepublic":
+			resp = b.makePublic(c, list)
+		case "movetopersonal":
+			resp = b.moveToPersonal(c, list)
+		case "movetoguild":
+			resp = b.moveToGuild(c, list)
+		case "access":
+			resp = b.accessList(c, list, arg)
+		case "lock":
+			resp = b.lockList(c, list, true)
+		case "unlock":
+			resp = b.lockList(c, list, false)
 		case "delete", "d":
-			resp = b.deleteList(guild, list, user, roles)
+			resp = b.deleteList(c, list)
 		case "edit", "e":
-			resp = b.editInList(guild, list, arg, user, roles)
+			resp = b.editInList(c, list, arg)
 		case "get", "g":
-			resp = b.getList(guild, list, arg, user, roles)
+			resp = b.getList(c, list, arg)
 		case "help", "h":
 			resp = b.help(strings.ToLower(list))
 		case "list", "l":
-			resp = b.listLists(guild, user, roles)
+			resp = b.listLists(c)
+		case "inc":
+			resp = b.changeQuantity(c, list, arg, true)
+		case "dec":
+			resp = b.changeQuantity(c, list, arg, false)
+		case "assign":
+			assignee := user
+			if len(m.Mentions) != 0 {
+				assignee = m.Mentions[0].ID
+			}
+			resp = b.assignInList(c, list, arg, assignee)
+		case "unassign":
+			resp = b.assignInList(c, list, arg, "")
+		case "mine":
+			resp = b.mine(c)
+		case "tag":
+			resp = b.tagInList(c, list, arg, true)
+		case "untag":
+			resp = b.tagInList(c, list, arg, false)
+		case "check", "done":
+			resp = b.checkInList(c, list, arg, true)
+		case "uncheck", "undone":
+			resp = b.checkInList(c, list, arg, false)
+		case "vote", "v":
+			resp = b.voteInList(c, list, arg, true)
+		case "unvote":
+			resp = b.voteInList(c, list, arg, false)
+		case "results":
+			resp = b.results(c, list)
+		case "poll":
+			resp = b.pollList(c, list, arg)
+		case "react":
+			resp = b.reactList(c, list, arg)
+		case "pin":
+			resp = b.pinList(c, list)
+		case "unpin":
+			resp = b.unpinList(c, list)
+		case "schedule":
+			resp = b.scheduleList(c, list, arg)
+		case "note", "n":
+			resp = b.noteInList(c, list, arg)
+		case "show":
+			resp = b.showItem(c, list, arg)
+		case "move", "m":
+			resp = b.moveInList(c, list, arg)
+		case "top":
+			resp = b.moveToEnd(c, list, arg, true)
+		case "bottom":
+			resp = b.moveToEnd(c, list, arg, false)
+		case "swap":
+			resp = b.swapInList(c, list, arg)
+		case "template", "t":
+			resp = b.template(c, strings.ToLower(list), arg)
+		case "share":
+			var friend string
+			if len(m.Mentions) != 0 {
+				friend = m.Mentions[0].ID
+			}
+			resp = b.shareList(c, list, friend, arg)
+		case "unshare":
+			var friend string
+			if len(m.Mentions) != 0 {
+				friend = m.Mentions[0].ID
+			}
+			resp = b.unshareList(c, list, friend)
+		case "join":
+			resp = b.joinList(c, list)
 		case "ping":
 			resp = b.ping()
 		case "createprivate", "cp":
+			bind, rest, msg := parseBinding(c, arg)
+			if msg != nil {
+				resp = msg
+				break
+			}
+
+			// Read only lists can be seen by everybody, but only changed by those given access.
+			var lType lists.ListType = lists.PrivateList
+			for _, a := range strings.Fields(rest) {
+				if strings.ToLower(a) == "--readonly" {
+					lType = lists.RestrictedList
+				}
+			}
+
 			var access []string
 
 			if len(m.MentionRoles) != 0 {
@@ -153,7 +382,7 @@ func (b *bot) messageHandler() func(s *discordgo.Session, m *discordgo.MessageCr
 				access = []string{user}
 			}
 
-			resp = b.createList(guild, list, dm, access)
+			resp = b.createList(c, list, lType, access, bind)
 		case "addtoprivate", "ap":
 			var access []string
 			if len(m.MentionRoles) != 0 {
@@ -168,7 +397,7 @@ func (b *bot) messageHandler() func(s *discordgo.Session, m *discordgo.MessageCr
 				}
 			}
 
-			resp = b.addAccessToList(guild, list, access, user, roles)
+			resp = b.addAccessToList(c, list, access)
 		case "removefromprivate", "rp":
 			var access []string
 			if len(m.MentionRoles) != 0 {
@@ -183,21 +412,30 @@ func (b *bot) messageHandler() func(s *discordgo.Session, m *discordgo.MessageCr
 				}
 			}
 
-			resp = b.removeAccessFromList(guild, list, access, user, roles)
+			resp = b.removeAccessFromList(c, list, access)
 		case "random", "rv":
-			resp = b.randomFromList(guild, list, user, roles)
+			resp = b.randomFromList(c, list, arg)
+		case "weight", "w":
+			resp = b.weightInList(c, list, arg)
+		case "set":
+			resp = b.setOption(c, list, arg)
 		case "remove", "r":
-			resp = b.removeFromList(guild, list, arg, user, roles)
+			resp = b.removeFromList(c, list, arg)
 		case "sort", "s":
-			resp = b.sortList(guild, list, arg, user, roles)
+			resp = b.sortList(c, list, arg)
 		}
 
 		if resp != nil {
-			_, err := s.ChannelMessageSendEmbed(channel, resp)
-			if err != nil {
-				fmt.Println("failed to send to discord", err)
-				_, _ = s.ChannelMessageSendEmbed(channel, failMsg())
-			}
+			b.reply(s, channel, resp)
 		}
 	}
 }
+
+// reply sends a response to a command, falling back to a generic failure if it can't be sent.
+func (b *bot) reply(s *discordgo.Session, channel string, resp *discordgo.MessageEmbed) {
+	_, err := s.ChannelMessageSendEmbed(channel, resp)
+	if err != nil {
+		fmt.Println("failed to send to discord", err)
+		_, _ = s.ChannelMessageSendEmbed(channel, failMsg())
+	}
+}
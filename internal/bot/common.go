@@ -2,6 +2,7 @@ package bot
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/DarkieSouls/listto/internal/lists"
@@ -23,6 +24,40 @@ func failMsg() *discordgo.MessageEmbed {
 	}
 }
 
+// timeoutMsg returns a message saying a command took too long, and should be tried again.
+func timeoutMsg() *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Description: "Sorry, that took me too long. Please try again in a moment",
+		Color:       red,
+	}
+}
+
+// unavailableMsg returns a message saying storage can't be used right now.
+func unavailableMsg() *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Description: "Sorry, storage is temporarily unavailable. Please try again in a little while",
+		Color:       red,
+	}
+}
+
+// storageErrMsg returns the message for a failed storage call, saying if it timed out or storage is unavailable.
+func storageErrMsg(err *listtoErr.ListtoError) *discordgo.MessageEmbed {
+	return storageErrMsgOr(err, failMsg())
+}
+
+// storageErrMsgOr returns the message for a failed storage call,
+// using msg unless the call timed out or storage is unavailable.
+func storageErrMsgOr(err *listtoErr.ListtoError, msg *discordgo.MessageEmbed) *discordgo.MessageEmbed {
+	switch err.Code {
+	case listtoErr.Timeout:
+		return timeoutMsg()
+	case listtoErr.Unavailable:
+		return unavailableMsg()
+	}
+
+	return msg
+}
+
 func noList(list string) *discordgo.MessageEmbed {
 	return &discordgo.MessageEmbed{
 		Description: fmt.Sprintf("I couldn't find a list called %s", list),
@@ -37,6 +72,27 @@ func noPerms(list string) *discordgo.MessageEmbed {
 	}
 }
 
+// readOnly returns a message saying the user can see a list, but not change it.
+func readOnly(lis *lists.ListtoList) *discordgo.MessageEmbed {
+	if lis.Locked {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is locked, so it can't be changed until it's unlocked", lis.Name),
+			Color:       yellow,
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("You can only read %s, not change it", lis.Name),
+		Color:       yellow,
+	}
+}
+
+// notFound returns if an error from Discord says that what was asked for doesn't exist.
+func notFound(err error) bool {
+	restErr, ok := err.(*discordgo.RESTError)
+	return ok && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound
+}
+
 // ping the bot.
 func (b *bot) ping() *discordgo.MessageEmbed {
 	return &discordgo.MessageEmbed{
@@ -64,12 +120,25 @@ func (b *bot) help(arg string) *discordgo.MessageEmbed {
 					Value: fmt.Sprintf("Creates a new list. Lists cannot contain spaces"+
 						"\nIf you send this in a DM, then the list will be personal. Only you can access a personal list, but you can access it anywhere I can see"+
 						"\nYou can only access personal lists in DMs, public or private lists need to be accessed on their servers"+
-						"\n__Example__:\n%screate MyList", p),
+						"\nYou can also create a list from a template with from:"+
+						"\nAdd --here to only show the list in this channel, or --category for this channel's category"+
+						"\n__Examples__:\n%screate MyList\n%sc Raid from:RaidChecklist\n%sc Standup --here", p, p, p),
+				},
+				{
+					Name: "default",
+					Value: fmt.Sprintf("Sets the default list for this channel, so item commands can leave out the list name. Use --clear to remove it, or leave the list out to see the current default"+
+						"\n__Examples__:\n%sdefault Groceries\n%sadd eggs\n%sdefault --clear", p, p, p),
 				},
 				{
 					Name: "createprivate, cp",
 					Value: fmt.Sprintf("Creates a new private list. You can specify allowed users and roles after the list name. Will default to just you if left blank"+
-						"\n__Examples__:\n%screateprivate MyList @UserOne\n%scp MyList @MyRole", p, p),
+						"\nAdd --readonly to let everybody read the list, while only those allowed can change it"+
+						"\n__Examples__:\n%screateprivate MyList @UserOne\n%scp MyList @MyRole\n%scp Announcements @Mods --readonly", p, p, p),
+				},
+				{
+					Name: "lock, unlock",
+					Value: fmt.Sprintf("Locks a list so nobody can change it, or unlocks it again. Locking a public list needs the Manage Messages permission"+
+						"\n__Examples__:\n%slock MyList\n%sunlock MyList", p, p),
 				},
 				{
 					Name:  "addtoprivate, ap",
@@ -79,17 +148,88 @@ func (b *bot) help(arg string) *discordgo.MessageEmbed {
 					Name:  "removefromprivate, rp",
 					Value: fmt.Sprintf("Removes the specified roles or users from a private list\n__Example__:\n%srp MyList @Role", p),
 				},
+				{
+					Name: "makeprivate, makepublic",
+					Value: fmt.Sprintf("Changes a list on the server to private or public. Only server admins can do this. A public list made private starts with just you and anybody you mention allowed on it"+
+						"\n__Examples__:\n%smakeprivate MyList @MyRole\n%smakepublic MyList", p, p),
+				},
+				{
+					Name: "movetopersonal, movetoguild",
+					Value: fmt.Sprintf("Moves a list from the server to your personal lists, or one of your personal lists onto the server as a private list. Moving a list off the server needs a server admin"+
+						"\n__Examples__:\n%smovetopersonal MyList\n%smovetoguild MyList", p, p),
+				},
+				{
+					Name: "access",
+					Value: fmt.Sprintf("Shows the users and roles allowed on a list, marking any that no longer exist. Add prune to remove those"+
+						"\n__Examples__:\n%saccess MyList\n%saccess MyList prune", p, p),
+				},
+				{
+					Name: "share, unshare",
+					Value: fmt.Sprintf("Shares a personal list with somebody, wherever they are. Add rw to let them change it, otherwise they can only read it"+
+						"\nThey will get a code to send me with join. Use unshare to take their access away"+
+						"\n__Examples__:\n%sshare MyList @Friend rw\n%sunshare MyList @Friend", p, p),
+				},
+				{
+					Name:  "join",
+					Value: fmt.Sprintf("Joins a personal list that somebody has shared with you\n__Example__:\n%sjoin ABCD2345", p),
+				},
 				{
 					Name:  "delete, d",
-					Value: fmt.Sprintf("Deletes a list\n__Example__:\n%sdelete MyList", p),
+					Value: fmt.Sprintf("Deletes a list. Deleting a public list needs the Manage Messages permission\n__Example__:\n%sdelete MyList", p),
+				},
+				{
+					Name: "get, g",
+					Value: fmt.Sprintf("Gets a list. You can give some tags, or by:@user, to only show matching items, and -v to see who added and edited each item"+
+						"\n__Examples__:\n%sget MyList\n%sg Backlog #bug\n%sg MyList -v by:@UserOne", p, p, p),
+				},
+				{
+					Name: "set",
+					Value: fmt.Sprintf("Changes a setting on a list, or shows the current settings if none is given"+
+						"\n**deck**: random picks go through every item before repeating any"+
+						"\n**autoremove**: items are removed when their quantity reaches zero"+
+						"\n**notify**: users are sent a DM when an item is assigned to them"+
+						"\n__Examples__:\n%sset MyList\n%sset MyList deck on", p, p),
+				},
+				{
+					Name: "react",
+					Value: fmt.Sprintf("Posts a list with numbered reactions. Clicking one checks that item off, or removes it or votes for it if you ask for remove or vote. Only the first 10 items get reactions"+
+						"\n__Examples__:\n%sreact Chores\n%sreact MyList remove\n%sreact Games vote", p, p, p),
+				},
+				{
+					Name: "vote, v, unvote",
+					Value: fmt.Sprintf("Votes for an item in a list, or takes your vote back. You can vote for each item once"+
+						"\n__Examples__:\n%svote Games Chess\n%sunvote Games 0", p, p),
+				},
+				{
+					Name: "poll",
+					Value: fmt.Sprintf("Runs a poll on the first 10 items in a list, for an hour unless you give a duration. Add reorder to sort the list by the results when the poll closes"+
+						"\n__Examples__:\n%spoll Games\n%spoll Movies 2d reorder", p, p),
 				},
 				{
-					Name:  "get, g",
-					Value: fmt.Sprintf("Gets a list\n__Example__:\n%sget MyList", p),
+					Name:  "results",
+					Value: fmt.Sprintf("Shows the items in a list ranked by votes\n__Example__:\n%sresults Games", p),
+				},
+				{
+					Name: "pin, unpin",
+					Value: fmt.Sprintf("Posts a list that I keep up to date whenever the list changes, or stops me updating it. A list can only have one pinned message"+
+						"\n__Examples__:\n%spin MyList\n%sunpin MyList", p, p),
+				},
+				{
+					Name: "schedule",
+					Value: fmt.Sprintf("Resets a list on a schedule, either clearing it, unchecking every item, or restoring it from a template."+
+						" Schedules can be daily, weekly or monthly, with an optional time zone. Mention a channel, or say here, to get a summary of each period before it resets."+
+						" Leave the schedule out to see it, or say off to stop it"+
+						"\n__Examples__:\n%sschedule Chores uncheck daily 06:00 Europe/London here\n%sschedule Raid from:RaidChecklist weekly wednesday 19:00 #raids\n%sschedule Chores off", p, p, p),
+				},
+				{
+					Name: "template, t",
+					Value: fmt.Sprintf("Saves a list's items and settings as a template for creating new lists, lists templates, or deletes one you made."+
+						" Add personal when saving to keep the template to yourself"+
+						"\n__Examples__:\n%stemplate save MyList RaidChecklist\n%st save MyList MyChecklist personal\n%st list\n%st delete RaidChecklist", p, p, p, p),
 				},
 				{
 					Name:  "sort, s",
-					Value: fmt.Sprintf("Sorts a list by either name or time\n__Example__\n%ssort MyList name", p),
+					Value: fmt.Sprintf("Sorts a list by either name, time or votes\n__Example__\n%ssort MyList name", p),
 				},
 			},
 		}
@@ -99,8 +239,40 @@ func (b *bot) help(arg string) *discordgo.MessageEmbed {
 			Color:       blue,
 			Fields: []*discordgo.MessageEmbedField{
 				{
-					Name:  "add, a",
-					Value: fmt.Sprintf("Adds an item to a list, items can have spaces\n__Example__:\n%sadd MyList My Item", p),
+					Name: "add, a",
+					Value: fmt.Sprintf("Adds an item to a list, items can have spaces. You can add a quantity in front of the item, and adding an item that's already on the list adds to its quantity"+
+						"\n__Examples__:\n%sadd MyList My Item\n%sa Groceries 3x eggs\n%sa Backlog Fix login #bug #urgent", p, p, p),
+				},
+				{
+					Name: "check, uncheck",
+					Value: fmt.Sprintf("Checks an item off in a list, or unchecks it again. You can specify the item by it's path or value"+
+						"\n__Examples__:\n%scheck Chores 0\n%suncheck Chores Wash up", p, p),
+				},
+				{
+					Name: "note, n",
+					Value: fmt.Sprintf("Adds a note to an item in a list, replacing any note it already has. Leave the note out to remove it."+
+						" You can specify the item by it's index, or it's value surrounded by \"s"+
+						"\n__Examples__:\n%snote MyList 0 Some more details\n%sn MyList \"My Item\" Some more details", p, p),
+				},
+				{
+					Name:  "show",
+					Value: fmt.Sprintf("Shows an item in a list with its note and everything else I know about it\n__Example__:\n%sshow MyList My Item", p),
+				},
+				{
+					Name: "addchild, ac",
+					Value: fmt.Sprintf("Adds an item underneath another item in a list, so lists can be nested. Nested items are found by their path, so 1.2 is the third item under the second item."+
+						" Paths can be used to get, edit or remove nested items"+
+						"\n__Examples__:\n%saddchild MyList 1 My Task\n%sac MyList 1.2 My Subtask\n%sr MyList 1.2", p, p, p),
+				},
+				{
+					Name: "tag, untag",
+					Value: fmt.Sprintf("Adds tags to, or removes tags from, an item in a list. You can also add tags when adding an item"+
+						"\n__Examples__:\n%stag Backlog Fix login #bug\n%suntag Backlog 0 #urgent", p, p),
+				},
+				{
+					Name: "inc, dec",
+					Value: fmt.Sprintf("Increases or decreases the quantity of an item in a list, by one unless you say otherwise"+
+						"\n__Examples__:\n%sinc Groceries eggs\n%sdec Groceries eggs 2", p, p),
 				},
 				{
 					Name: "edit, e",
@@ -114,8 +286,37 @@ func (b *bot) help(arg string) *discordgo.MessageEmbed {
 						"\n__Example__:\n%sg MyList 0", p),
 				},
 				{
-					Name:  "random, rv",
-					Value: fmt.Sprintf("Selects a random item from a list\n__Example__:\n%srv MyList", p),
+					Name: "assign, unassign",
+					Value: fmt.Sprintf("Assigns an item in a list to a user, or to you if nobody is mentioned. Unassign removes the assignment"+
+						"\n__Examples__:\n%sassign MyList My Item @UserOne\n%sunassign MyList 0", p, p),
+				},
+				{
+					Name:  "mine",
+					Value: fmt.Sprintf("Lists every item assigned to you in the lists you can access\n__Example__:\n%smine", p),
+				},
+				{
+					Name: "move, m",
+					Value: fmt.Sprintf("Moves an item to a new position in a list. You can specify the item by it's index or value, followed by the new position"+
+						"\n__Examples__:\n%smove MyList 3 0\n%sm MyList My Item 2", p, p),
+				},
+				{
+					Name:  "top, bottom",
+					Value: fmt.Sprintf("Moves an item to the top or bottom of a list\n__Examples__:\n%stop MyList My Item\n%sbottom MyList 0", p, p),
+				},
+				{
+					Name: "swap",
+					Value: fmt.Sprintf("Swaps the positions of two items in a list. Specify them by index, or by value surrounded by \"s"+
+						"\n__Examples__:\n%sswap MyList 0 3\n%sswap MyList \"My Item\" \"Other Item\"", p, p),
+				},
+				{
+					Name: "random, rv",
+					Value: fmt.Sprintf("Selects random items from a list. You can ask for more than one item, and you won't get the same item twice"+
+						"\n__Examples__:\n%srv MyList\n%srandom MyList 3", p, p),
+				},
+				{
+					Name: "weight, w",
+					Value: fmt.Sprintf("Sets how likely an item is to be picked at random. An item with a weight of 3 is three times as likely to be picked as a normal item"+
+						"\n__Example__:\n%sweight MyList My Item 3", p),
 				},
 				{
 					Name: "remove, r",
@@ -124,6 +325,30 @@ func (b *bot) help(arg string) *discordgo.MessageEmbed {
 				},
 			},
 		}
+	case "admin":
+		return &discordgo.MessageEmbed{
+			Description: "Here are some commands for server admins. Members with Manage Server, or the admin role, can manage every list on the server:",
+			Color:       blue,
+			Fields: []*discordgo.MessageEmbedField{
+				{
+					Name:  "admin",
+					Value: fmt.Sprintf("Shows the admin settings for the server. Needs the Manage Server permission\n__Example__:\n%sadmin", p),
+				},
+				{
+					Name:  "admin role",
+					Value: fmt.Sprintf("Sets a role that can manage every list on the server, or none to remove it\n__Examples__:\n%sadmin role @ListtoAdmin\n%sadmin role none", p, p),
+				},
+				{
+					Name:  "admin publicdelete",
+					Value: fmt.Sprintf("Lets anybody delete public lists. Otherwise it needs the Manage Messages permission\n__Example__:\n%sadmin publicdelete on", p),
+				},
+				{
+					Name: "limits",
+					Value: fmt.Sprintf("Shows or changes how many lists the server can have, how many items a list can hold, and how long items can be. Use default to go back to the default limit"+
+						"\n__Examples__:\n%slimits\n%slimits items 100\n%slimits length default", p, p, p),
+				},
+			},
+		}
 	default:
 		return &discordgo.MessageEmbed{
 			Description: "Listto does some list management things! Here's some generic commands:",
@@ -131,7 +356,7 @@ func (b *bot) help(arg string) *discordgo.MessageEmbed {
 			Fields: []*discordgo.MessageEmbedField{
 				{
 					Name:  "help, h",
-					Value: fmt.Sprintf("Displays a help message!\nCan accept arguments of lists and items\n__Examples__:\n%shelp\n%sh lists", p, p),
+					Value: fmt.Sprintf("Displays a help message!\nCan accept arguments of lists, items and admin\n__Examples__:\n%shelp\n%sh lists", p, p),
 				},
 				{
 					Name:  "list, l",
@@ -146,26 +371,40 @@ func (b *bot) help(arg string) *discordgo.MessageEmbed {
 	}
 }
 
-func (b *bot) getDDBList(guild, list, user string) (*lists.ListtoList, *discordgo.MessageEmbed) {
-	lis, err := b.DDB.GetList(guild, list)
+// getDDBList gets a list visible to the caller, falling back to their personal lists,
+// then to personal lists that have been shared with them.
+// Lists bound to another channel or category are treated as not existing.
+func (b *bot) getDDBList(c *caller, list string) (*lists.ListtoList, *discordgo.MessageEmbed) {
+	lis, err := b.findList(c, list)
 	if err != nil {
 		if err.Code == listtoErr.ListNotFound {
-			if guild != user {
-				lis2, err2 := b.DDB.GetList(user, list)
-				if err2 != nil {
-					if err2.Code == listtoErr.ListNotFound {
-						return nil, noList(list)
-					}
-					err.LogError()
-					return nil, failMsg()
-				}
-				return lis2, nil
-			}
 			return nil, noList(list)
 		}
 		err.LogError()
-		return nil, failMsg()
+		return nil, storageErrMsg(err)
 	}
 
 	return lis, nil
 }
+
+// findList looks a list up in the same way as getDDBList, using the list already found for the command if there is one.
+func (b *bot) findList(c *caller, list string) (*lists.ListtoList, *listtoErr.ListtoError) {
+	if c.found != nil && c.found.Name == list {
+		lis := c.found
+		c.found = nil
+		return lis, nil
+	}
+
+	lis, err := b.DDB.GetList(c.ctx, c.guild, list)
+	if err == nil && !lis.VisibleIn(c.channel, c.category) {
+		err = listtoErr.ListNotFoundError(list)
+	}
+	if err != nil && err.Code == listtoErr.ListNotFound && c.guild != c.user {
+		lis, err = b.DDB.GetList(c.ctx, c.user, list)
+	}
+	if err != nil && err.Code == listtoErr.ListNotFound {
+		lis, err = b.getJoinedList(c, list)
+	}
+
+	return lis, err
+}
@@ -0,0 +1,210 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// makePrivate turns a public or read only list into a private one.
+// Public lists start with just the caller and anybody mentioned allowed on them.
+func (b *bot) makePrivate(c *caller, list string, access []string) *discordgo.MessageEmbed {
+	lis, msg := b.getConvertibleList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if lis.Type == lists.PrivateList {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is already private", list),
+			Color:       yellow,
+		}
+	}
+
+	if lis.Type == lists.PublicList {
+		lis.Access = []string{c.user}
+	}
+	lis.Type = lists.PrivateList
+	lis.AddAccess(access)
+
+	return b.saveConverted(c.ctx, lis, "private")
+}
+
+// makePublic turns a private or read only list into a public one, that anybody can use.
+func (b *bot) makePublic(c *caller, list string) *discordgo.MessageEmbed {
+	lis, msg := b.getConvertibleList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if lis.Type == lists.PublicList {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is already public", list),
+			Color:       yellow,
+		}
+	}
+
+	lis.Type = lists.PublicList
+	lis.Access = nil
+
+	return b.saveConverted(c.ctx, lis, "public")
+}
+
+// moveToPersonal moves a list from the server to the caller's personal lists, where only they can use it.
+func (b *bot) moveToPersonal(c *caller, list string) *discordgo.MessageEmbed {
+	lis, msg := b.getConvertibleList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	from := lis.Guild
+	pin := lis.Pin
+	lis.Pin = nil
+
+	lis.Guild = c.user
+	lis.Type = lists.PersonalList
+	lis.Access = []string{c.user}
+	lis.Viewers = nil
+	lis.Channel = ""
+
+	if msg := b.checkNewList(c.ctx, lis); msg != nil {
+		return msg
+	}
+
+	if err := b.DDB.MoveList(c.ctx, lis, from); err != nil {
+		if err.Code == listtoErr.ListExists {
+			return &discordgo.MessageEmbed{
+				Description: fmt.Sprintf("You already have a personal list called %s", list),
+				Color:       yellow,
+			}
+		}
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't move %s", list),
+			Color:       red,
+		})
+	}
+
+	// Only say the list has moved once it has.
+	lis.Pin = pin
+	b.retirePin(lis, "This list has been moved")
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have moved %s to your personal lists", list),
+		Color:       green,
+	}
+}
+
+// moveToGuild moves one of the caller's personal lists onto the server as a private list,
+// keeping everybody it was shared with.
+func (b *bot) moveToGuild(c *caller, list string) *discordgo.MessageEmbed {
+	if c.dm {
+		return &discordgo.MessageEmbed{
+			Description: "You need to send this on the server you want to move the list to",
+			Color:       yellow,
+		}
+	}
+
+	lis, err := b.DDB.GetList(c.ctx, c.user, list)
+	if err != nil {
+		if err.Code == listtoErr.ListNotFound {
+			return &discordgo.MessageEmbed{
+				Description: fmt.Sprintf("You don't have a personal list called %s", list),
+				Color:       yellow,
+			}
+		}
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	from := lis.Guild
+	pin := lis.Pin
+	lis.Pin = nil
+
+	lis.Guild = c.guild
+	lis.Type = lists.PrivateList
+
+	if msg := b.checkNewList(c.ctx, lis); msg != nil {
+		return msg
+	}
+
+	if err := b.DDB.MoveList(c.ctx, lis, from); err != nil {
+		if err.Code == listtoErr.ListExists {
+			return &discordgo.MessageEmbed{
+				Description: fmt.Sprintf("There is already a list called %s on this server", list),
+				Color:       yellow,
+			}
+		}
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't move %s", list),
+			Color:       red,
+		})
+	}
+
+	lis.Pin = pin
+	b.retirePin(lis, "This list has been moved")
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have moved %s onto this server as a private list", list),
+		Color:       green,
+	}
+}
+
+// getConvertibleList gets a list on the server, as long as the caller is a server admin.
+func (b *bot) getConvertibleList(c *caller, list string) (*lists.ListtoList, *discordgo.MessageEmbed) {
+	if c.dm {
+		return nil, &discordgo.MessageEmbed{
+			Description: "Personal lists can only be moved onto a server",
+			Color:       yellow,
+		}
+	}
+
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return nil, msg
+	}
+
+	if lis.Type == lists.PersonalList {
+		return nil, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is a personal list. Use %smovetoguild to move it onto this server first", list, b.Config.Prefix),
+			Color:       yellow,
+		}
+	}
+
+	// Changing a list's type or moving it off the server affects everybody using it, so is left to admins.
+	admin, err := b.isAdmin(c)
+	if err != nil {
+		err.LogError()
+		return nil, storageErrMsg(err)
+	}
+
+	if !admin {
+		return nil, &discordgo.MessageEmbed{
+			Description: "You need to be a server admin to change a list's type or move it off the server",
+			Color:       yellow,
+		}
+	}
+
+	return lis, nil
+}
+
+// saveConverted stores a list that has changed type.
+func (b *bot) saveConverted(ctx context.Context, lis *lists.ListtoList, lType string) *discordgo.MessageEmbed {
+	if err := b.putList(ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't make %s %s", lis.Name, lType),
+			Color:       red,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have made %s %s", lis.Name, lType),
+		Color:       green,
+	}
+}
@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// defaultCommands are the commands that use the channel's default list when they aren't given one.
+var defaultCommands = map[string]bool{
+	"add": true, "a": true,
+	"addchild": true, "ac": true,
+	"edit": true, "e": true,
+	"get": true, "g": true,
+	"remove": true, "r": true,
+	"inc": true, "dec": true,
+	"assign": true, "unassign": true,
+	"tag": true, "untag": true,
+	"check": true, "done": true,
+	"uncheck": true, "undone": true,
+	"vote": true, "v": true,
+	"unvote": true, "results": true,
+	"note": true, "n": true,
+	"show": true,
+	"move": true, "m": true,
+	"top": true, "bottom": true, "swap": true,
+	"random": true, "rv": true,
+}
+
+// withDefault returns the list and argument a command should use, given the channel's default list.
+// If the first word doesn't name a list the caller can see, it is treated as part of the argument,
+// so "add eggs" adds eggs to the default list. A list that is found is kept for the command, so it isn't looked up twice.
+func (b *bot) withDefault(c *caller, list, arg string) (string, string, *discordgo.MessageEmbed) {
+	if list != "" {
+		lis, err := b.findList(c, list)
+		if err == nil {
+			c.found = lis
+			return list, arg, nil
+		}
+		if err.Code != listtoErr.ListNotFound {
+			err.LogError()
+			return list, arg, storageErrMsg(err)
+		}
+	}
+
+	settings, err := b.DDB.GetSettings(c.ctx, c.guild)
+	if err != nil {
+		err.LogError()
+		return list, arg, storageErrMsg(err)
+	}
+
+	def := settings.DefaultList(c.channel)
+	if def == "" || def == list {
+		return list, arg, nil
+	}
+
+	return def, strings.TrimSpace(list + " " + arg), nil
+}
+
+// defaultList shows, sets or removes the default list for the channel.
+func (b *bot) defaultList(c *caller, list string) *discordgo.MessageEmbed {
+	settings, err := b.DDB.GetSettings(c.ctx, c.guild)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	switch strings.ToLower(list) {
+	case "":
+		def := settings.DefaultList(c.channel)
+		if def == "" {
+			return &discordgo.MessageEmbed{
+				Description: "This channel doesn't have a default list",
+				Color:       yellow,
+			}
+		}
+
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("The default list for this channel is %s", def),
+			Color:       green,
+		}
+	case "--clear":
+		settings.SetDefault(c.channel, "")
+	default:
+		lis, msg := b.getDDBList(c, list)
+		if msg != nil {
+			return msg
+		}
+
+		if msg = b.checkAccess(c, lis); msg != nil {
+			return msg
+		}
+
+		settings.SetDefault(c.channel, list)
+	}
+
+	if err := b.DDB.PutSettings(c.ctx, settings); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: "I couldn't update the default list for this channel",
+			Color:       red,
+		})
+	}
+
+	if def := settings.DefaultList(c.channel); def != "" {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is now the default list for this channel", def),
+			Color:       green,
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: "This channel no longer has a default list",
+		Color:       green,
+	}
+}
@@ -0,0 +1,126 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// dueKey identifies a schedule or poll by the list it belongs to.
+type dueKey struct {
+	guild string
+	name  string
+}
+
+// dueLoader scans storage for when every schedule or poll is due.
+type dueLoader func(ctx context.Context) (map[dueKey]int64, *listtoErr.ListtoError)
+
+// dueChecker reads a schedule or poll that looks due, and runs it if it still is.
+type dueChecker func(ctx context.Context, k dueKey, now time.Time)
+
+// dueTimes keeps when each schedule or poll is next due, so their tables are only scanned once when the bot starts,
+// rather than every time they are checked. Anything due is read again before it is acted on, so the times only need to be close.
+type dueTimes struct {
+	sync.Mutex
+	loaded bool
+	times  map[dueKey]int64
+}
+
+// newDueTimes creates an empty dueTimes, which needs loading before anything is due.
+func newDueTimes() *dueTimes {
+	return &dueTimes{times: make(map[dueKey]int64)}
+}
+
+// load fills in the times scanned from storage. Times set since the scan started are newer, so are kept.
+func (d *dueTimes) load(times map[dueKey]int64) {
+	d.Lock()
+	defer d.Unlock()
+
+	for k, t := range times {
+		if _, ok := d.times[k]; !ok {
+			d.times[k] = t
+		}
+	}
+	d.loaded = true
+}
+
+// isLoaded returns if the times have been loaded from storage yet.
+func (d *dueTimes) isLoaded() bool {
+	d.Lock()
+	defer d.Unlock()
+
+	return d.loaded
+}
+
+// set records when a schedule or poll is next due.
+func (d *dueTimes) set(guild, name string, at int64) {
+	d.Lock()
+	defer d.Unlock()
+
+	d.times[dueKey{guild: guild, name: name}] = at
+}
+
+// remove forgets a schedule or poll that has been deleted.
+func (d *dueTimes) remove(guild, name string) {
+	d.Lock()
+	defer d.Unlock()
+
+	delete(d.times, dueKey{guild: guild, name: name})
+}
+
+// due returns the schedules or polls that are due.
+func (d *dueTimes) due(now time.Time) []dueKey {
+	d.Lock()
+	defer d.Unlock()
+
+	var keys []dueKey
+	for k, t := range d.times {
+		if now.Unix() >= t {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// runDue checks every minute for schedules or polls that are due, handing each one to check.
+// Until load succeeds it is tried again on every tick, and nothing is due.
+func (b *bot) runDue(d *dueTimes, load dueLoader, check dueChecker) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopped:
+			return
+		case now := <-ticker.C:
+			if b.begin() {
+				b.checkDue(d, load, check, now)
+				b.inFlight.Done()
+			}
+		}
+	}
+}
+
+// checkDue loads the due times if that hasn't been done yet, then checks everything that is due.
+func (b *bot) checkDue(d *dueTimes, load dueLoader, check dueChecker, now time.Time) {
+	if !d.isLoaded() {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		times, err := load(ctx)
+		cancel()
+		if err != nil {
+			err.LogError()
+			return
+		}
+		d.load(times)
+	}
+
+	for _, k := range d.due(now) {
+		// Each one gets its own deadline, so one slow call doesn't hold up the rest.
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		check(ctx, k, now)
+		cancel()
+	}
+}
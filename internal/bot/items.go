@@ -2,57 +2,171 @@ package bot
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
 )
 
+// quantityRegex matches a value with a quantity in front of it, such as "3x eggs".
+var quantityRegex = regexp.MustCompile(`^(\d+)x\s+(.+)$`)
+
+// mentionRegex matches a user mention in a message.
+var mentionRegex = regexp.MustCompile(`<@!?\d+>`)
+
+// channelRegex matches a channel mention in a message.
+var channelRegex = regexp.MustCompile(`^<#(\d+)>$`)
+
 // addToList adds a value to a list.
-func (b *bot) addToList(guild, list, arg, user string, roles []string) *discordgo.MessageEmbed {
-	lis, msg := b.getDDBList(guild, list, user)
+// A value can start with a quantity, such as "3x eggs", which is added to any existing entry,
+// and can contain hashtag style tags, such as "Fix login #bug".
+func (b *bot) addToList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
 	if msg != nil {
 		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	quantity := 1
+	if match := quantityRegex.FindStringSubmatch(arg); match != nil {
+		quantity, _ = strconv.Atoi(match[1])
+		arg = match[2]
+	}
+
+	if quantity < 1 {
+		return &discordgo.MessageEmbed{
+			Description: "I can only add a positive quantity of something!",
+			Color:       yellow,
+		}
 	}
 
-	dupe := "!"
-	for _, l := range lis.List {
-		if l.Value == arg {
-			dupe = ", again"
+	arg, tags := lists.ParseTags(arg)
+	if arg == "" {
+		return &discordgo.MessageEmbed{
+			Description: "You need to tell me what to add!",
+			Color:       yellow,
 		}
 	}
 
-	lis.AddItem(arg, time.Now().Unix())
+	existing := lis.FindItem(arg) != -1
+
+	if msg := b.checkItem(c.ctx, lis, arg, !existing); msg != nil {
+		return msg
+	}
+
+	count := lis.AddQuantity(arg, c.user, quantity, time.Now().Unix())
+	lis.AddTags(lis.FindItem(arg), tags)
 
-	if err := b.DDB.PutList(lis); err != nil {
+	if err := b.putList(c.ctx, lis); err != nil {
 		err.LogError()
-		return &discordgo.MessageEmbed{
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
 			Description: fmt.Sprintf("I couldn't add %s to %s", arg, list),
 			Color:       red,
+		})
+	}
+
+	if existing {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s already had %s, so there are now %d", list, arg, count),
+			Color:       green,
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I added %s to %s!", lis.List[lis.FindItem(arg)].Label(), list),
+		Color:       green,
+	}
+}
+
+// addChildToList adds a value underneath an existing item in the list.
+func (b *bot) addChildToList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	parent, value := splitItem(arg)
+	if value == "" {
+		return &discordgo.MessageEmbed{
+			Description: "You need to tell me which item to add to, and what to add!",
+			Color:       yellow,
+		}
+	}
+
+	path, ok := lists.ParsePath(parent)
+	if !ok {
+		i := lis.FindItem(parent)
+		if i == -1 {
+			return &discordgo.MessageEmbed{
+				Description: fmt.Sprintf("%s doesn't seem to contain %s", list, parent),
+				Color:       yellow,
+			}
+		}
+		path = []int{i}
+	}
+
+	if len(path) >= lists.MaxDepth {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Items can only be nested %d deep", lists.MaxDepth),
+			Color:       yellow,
+		}
+	}
+
+	if msg := b.checkItem(c.ctx, lis, value, true); msg != nil {
+		return msg
+	}
+
+	parent = lis.AddChild(path, value, c.user, time.Now().Unix())
+	if parent == "" {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't find an item at %s in %s", lists.FormatPath(path), list),
+			Color:       yellow,
 		}
 	}
 
-	ls := list + dupe
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
 
 	return &discordgo.MessageEmbed{
-		Description: fmt.Sprintf("I added %s to %s", arg, ls),
+		Description: fmt.Sprintf("I added %s under %s in %s!", value, parent, list),
 		Color:       green,
 	}
 }
 
-func (b *bot) editInList(guild, list, arg, user string, roles []string) *discordgo.MessageEmbed {
-	lis, msg := b.getDDBList(guild, list, user)
+func (b *bot) editInList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
 	if msg != nil {
 		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
 	}
 
 	var updated string
@@ -61,8 +175,8 @@ func (b *bot) editInList(guild, list, arg, user string, roles []string) *discord
 	switch len(args) {
 	case 1:
 		args = strings.Split(arg, " ")
-		i, err := strconv.Atoi(args[0])
-		if err != nil {
+		path, ok := lists.ParsePath(args[0])
+		if !ok {
 			return &discordgo.MessageEmbed{
 				Description: "The first argument needs to be a number or existing value!",
 				Color:       yellow,
@@ -70,8 +184,11 @@ func (b *bot) editInList(guild, list, arg, user string, roles []string) *discord
 		}
 
 		newVal := strings.Join(args[1:], " ")
+		if msg := b.checkItem(c.ctx, lis, newVal, false); msg != nil {
+			return msg
+		}
 
-		updated = lis.EditIndex(i, newVal)
+		updated = lis.EditPath(path, newVal, c.user, time.Now().Unix())
 		if updated == "" {
 			return &discordgo.MessageEmbed{
 				Description: fmt.Sprintf("%s doesn't seem to have that many items!", list),
@@ -80,7 +197,12 @@ func (b *bot) editInList(guild, list, arg, user string, roles []string) *discord
 		}
 	case 2:
 		updated = strings.TrimPrefix(args[0], "\"")
-		s := lis.EditItem(updated, strings.TrimSuffix(args[1], "\""))
+		newVal := strings.TrimSuffix(args[1], "\"")
+		if msg := b.checkItem(c.ctx, lis, newVal, false); msg != nil {
+			return msg
+		}
+
+		s := lis.EditItem(updated, newVal, c.user, time.Now().Unix())
 		if s == "" {
 			return &discordgo.MessageEmbed{
 				Description: fmt.Sprintf("%s doesn't seem to contain %s", list, updated),
@@ -94,9 +216,9 @@ func (b *bot) editInList(guild, list, arg, user string, roles []string) *discord
 		}
 	}
 
-	if err := b.DDB.PutList(lis); err != nil {
+	if err := b.putList(c.ctx, lis); err != nil {
 		err.LogError()
-		return failMsg()
+		return storageErrMsg(err)
 	}
 
 	return &discordgo.MessageEmbed{
@@ -105,38 +227,144 @@ func (b *bot) editInList(guild, list, arg, user string, roles []string) *discord
 	}
 }
 
-// randomFromList selects a random element from the list.
-func (b *bot) randomFromList(guild, list, user string, roles []string) *discordgo.MessageEmbed {
-	lis, msg := b.getDDBList(guild, list, user)
+// randomFromList selects random elements from the list.
+func (b *bot) randomFromList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
 	if msg != nil {
 		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	count := 1
+	if arg != "" {
+		var err error
+		count, err = strconv.Atoi(arg)
+		if err != nil || count < 1 {
+			return &discordgo.MessageEmbed{
+				Description: "The number of items to pick needs to be a positive number!",
+				Color:       yellow,
+			}
+		}
+	}
+
+	random := lis.SelectRandoms(count)
+	if len(random) == 0 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is empty, so I can't pick anything from it", list),
+			Color:       yellow,
+		}
+	}
+
+	// Deck mode keeps track of what has been drawn, so that needs saving.
+	// People who can't edit the list still get a pick, but don't use up the deck.
+	if lis.Deck {
+		ok, err := b.canEdit(c, lis)
+		if err != nil {
+			err.LogError()
+			return storageErrMsg(err)
+		}
+
+		if ok {
+			if err := b.putList(c.ctx, lis); err != nil {
+				err.LogError()
+				return storageErrMsg(err)
+			}
+		}
 	}
 
-	random := lis.SelectRandom()
+	if len(random) == 1 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("A random element from %s is %s", list, random[0]),
+			Color:       green,
+		}
+	}
 
 	return &discordgo.MessageEmbed{
-		Description: fmt.Sprintf("A random element from %s is %s", list, random),
+		Description: fmt.Sprintf("Here are %d random elements from %s", len(random), list),
+		Color:       green,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:  "Picked",
+				Value: strings.Join(random, "\n"),
+			},
+		},
+	}
+}
+
+// weightInList sets how likely an item is to be randomly picked.
+func (b *bot) weightInList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	split := strings.LastIndex(arg, " ")
+	if split == -1 {
+		return &discordgo.MessageEmbed{
+			Description: "You need to tell me the item and its weight!",
+			Color:       yellow,
+		}
+	}
+
+	weight, err := strconv.Atoi(arg[split+1:])
+	if err != nil || weight < 1 {
+		return &discordgo.MessageEmbed{
+			Description: "The weight needs to be a positive number!",
+			Color:       yellow,
+		}
+	}
+
+	item := arg[:split]
+	i := findItem(lis, item)
+	if i == -1 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, item),
+			Color:       yellow,
+		}
+	}
+
+	updated := lis.SetWeight(i, weight)
+	lis.MarkEdited(i, c.user, time.Now().Unix())
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have set the weight of %s in %s to %d", updated, list, weight),
 		Color:       green,
 	}
 }
 
 // removeFromList removes an item from the list.
-func (b *bot) removeFromList(guild, list, arg, user string, roles []string) *discordgo.MessageEmbed {
-	lis, msg := b.getDDBList(guild, list, user)
+func (b *bot) removeFromList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
 	if msg != nil {
 		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
 	}
 
-	i, err := strconv.Atoi(arg)
-	if err != nil {
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	path, ok := lists.ParsePath(arg)
+	if !ok {
 		s := lis.RemoveItem(arg)
 		if s == "" {
 			return &discordgo.MessageEmbed{
@@ -145,7 +373,7 @@ func (b *bot) removeFromList(guild, list, arg, user string, roles []string) *dis
 			}
 		}
 	} else {
-		arg = lis.RemoveIndex(i)
+		arg = lis.RemovePath(path)
 		if arg == "" {
 			return &discordgo.MessageEmbed{
 				Description: fmt.Sprintf("%s doesn't seem to have that many items!", list),
@@ -154,9 +382,9 @@ func (b *bot) removeFromList(guild, list, arg, user string, roles []string) *dis
 		}
 	}
 
-	if lisErr := b.DDB.PutList(lis); lisErr != nil {
+	if lisErr := b.putList(c.ctx, lis); lisErr != nil {
 		lisErr.LogError()
-		return failMsg()
+		return storageErrMsg(lisErr)
 	}
 
 	return &discordgo.MessageEmbed{
@@ -164,3 +392,592 @@ func (b *bot) removeFromList(guild, list, arg, user string, roles []string) *dis
 		Color:       green,
 	}
 }
+
+// changeQuantity increases or decreases the quantity of an item in the list.
+func (b *bot) changeQuantity(c *caller, list, arg string, increase bool) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	item, by := arg, 1
+	if split := strings.LastIndex(arg, " "); split != -1 {
+		if n, err := strconv.Atoi(arg[split+1:]); err == nil {
+			item, by = arg[:split], n
+		}
+	}
+
+	if by < 1 {
+		return &discordgo.MessageEmbed{
+			Description: "The amount to change by needs to be a positive number!",
+			Color:       yellow,
+		}
+	}
+
+	i := findItem(lis, item)
+	if i == -1 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, item),
+			Color:       yellow,
+		}
+	}
+
+	item = lis.List[i].Value
+	if !increase {
+		by = -by
+	}
+
+	lis.MarkEdited(i, c.user, time.Now().Unix())
+	count, removed := lis.ChangeQuantity(i, by)
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if removed {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("There are no %s left, so I have removed it from %s", item, list),
+			Color:       green,
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("%s now has %d of %s", list, count, item),
+		Color:       green,
+	}
+}
+
+// moveInList moves an item to a new position in the list.
+func (b *bot) moveInList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	split := strings.LastIndex(arg, " ")
+	if split == -1 {
+		return &discordgo.MessageEmbed{
+			Description: "You need to tell me the item and the position to move it to!",
+			Color:       yellow,
+		}
+	}
+
+	pos, err := strconv.Atoi(arg[split+1:])
+	if err != nil {
+		return &discordgo.MessageEmbed{
+			Description: "The position needs to be a number!",
+			Color:       yellow,
+		}
+	}
+
+	item := arg[:split]
+	i := findItem(lis, item)
+	if i == -1 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, item),
+			Color:       yellow,
+		}
+	}
+
+	moved := lis.MoveIndex(i, pos)
+	if moved == "" {
+		return &discordgo.MessageEmbed{
+			Description: "I can't move an item to that position!",
+			Color:       yellow,
+		}
+	}
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have moved %s in %s", moved, list),
+		Color:       green,
+	}
+}
+
+// moveToEnd moves an item to either the top or the bottom of the list.
+func (b *bot) moveToEnd(c *caller, list, arg string, top bool) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	i := findItem(lis, arg)
+	if i == -1 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, arg),
+			Color:       yellow,
+		}
+	}
+
+	pos, end := len(lis.List)-1, "bottom"
+	if top {
+		pos, end = 0, "top"
+	}
+
+	moved := lis.MoveIndex(i, pos)
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have moved %s to the %s of %s", moved, end, list),
+		Color:       green,
+	}
+}
+
+// swapInList swaps the positions of two items in the list.
+func (b *bot) swapInList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	args := strings.Split(arg, `" "`)
+	if len(args) == 1 {
+		args = strings.Split(arg, " ")
+	}
+
+	if len(args) != 2 {
+		return &discordgo.MessageEmbed{
+			Description: "You need to specify exactly two items to swap",
+			Color:       yellow,
+		}
+	}
+
+	var indexes [2]int
+	for n, a := range args {
+		a = strings.Trim(a, "\"")
+		indexes[n] = findItem(lis, a)
+		if indexes[n] == -1 {
+			return &discordgo.MessageEmbed{
+				Description: fmt.Sprintf("%s doesn't seem to contain %s", list, a),
+				Color:       yellow,
+			}
+		}
+	}
+
+	first, second := lis.List[indexes[0]].Value, lis.List[indexes[1]].Value
+	lis.SwapIndex(indexes[0], indexes[1])
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have swapped %s and %s in %s", first, second, list),
+		Color:       green,
+	}
+}
+
+// noteInList sets the note on an item in the list.
+func (b *bot) noteInList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	item, note := splitItem(arg)
+	i := findItem(lis, item)
+	if i == -1 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, item),
+			Color:       yellow,
+		}
+	}
+
+	item = lis.SetNote(i, note)
+	lis.MarkEdited(i, c.user, time.Now().Unix())
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if note == "" {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I have removed the note from %s in %s", item, list),
+			Color:       green,
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have updated the note on %s in %s", item, list),
+		Color:       green,
+	}
+}
+
+// checkInList marks an item in the list as done, or not done.
+func (b *bot) checkInList(c *caller, list, arg string, done bool) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	path := findPath(lis, arg)
+	item := lis.SetDone(path, done)
+	if item == "" {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, arg),
+			Color:       yellow,
+		}
+	}
+	lis.MarkEditedPath(path, c.user, time.Now().Unix())
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if done {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I have checked off %s in %s", item, list),
+			Color:       green,
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have unchecked %s in %s", item, list),
+		Color:       green,
+	}
+}
+
+// showItem shows everything known about an item in the list.
+func (b *bot) showItem(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	path := findPath(lis, arg)
+	item := lis.Item(path)
+	if item == nil {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, arg),
+			Color:       yellow,
+		}
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Position", Value: lists.FormatPath(path), Inline: true},
+	}
+
+	if item.Quantity != nil {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Quantity", Value: strconv.Itoa(item.Count()), Inline: true})
+	}
+
+	if item.Weight != 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Weight", Value: strconv.Itoa(item.Weight), Inline: true})
+	}
+
+	if item.Assignee != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Assigned to", Value: fmt.Sprintf("<@%s>", item.Assignee), Inline: true})
+	}
+
+	if len(item.Tags) != 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Tags", Value: "#" + strings.Join(item.Tags, " #"), Inline: true})
+	}
+
+	if item.AddedBy != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Added by", Value: fmt.Sprintf("<@%s>", item.AddedBy), Inline: true})
+	}
+
+	if item.TimeAdded != 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Added", Value: formatTime(item.TimeAdded), Inline: true})
+	}
+
+	if item.Done {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Done", Value: "Yes", Inline: true})
+	}
+
+	if len(item.Children) != 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Nested items", Value: strconv.Itoa(len(item.Children)), Inline: true})
+	}
+
+	if item.EditedBy != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Last edited by", Value: fmt.Sprintf("<@%s>", item.EditedBy), Inline: true})
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Last edited", Value: formatTime(item.TimeEdited), Inline: true})
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       item.Value,
+		Description: item.Note,
+		Color:       green,
+		Fields:      fields,
+		Footer:      &discordgo.MessageEmbedFooter{Text: list},
+	}
+}
+
+// splitItem splits an argument into an item and whatever follows it.
+// The item is either the first word, or a value surrounded by "s.
+func splitItem(arg string) (item, rest string) {
+	if strings.HasPrefix(arg, "\"") {
+		if end := strings.Index(arg[1:], "\""); end != -1 {
+			return arg[1 : end+1], strings.TrimSpace(arg[end+2:])
+		}
+	}
+
+	split := strings.Index(arg, " ")
+	if split == -1 {
+		return arg, ""
+	}
+
+	return arg[:split], strings.TrimSpace(arg[split+1:])
+}
+
+// formatTime prints a unix timestamp in a readable way.
+func formatTime(t int64) string {
+	return time.Unix(t, 0).UTC().Format("2 Jan 2006 15:04 MST")
+}
+
+// findPath returns the path to an item in a list, given either its path or its value.
+// The path won't lead to an item if the item can't be found.
+func findPath(lis *lists.ListtoList, item string) []int {
+	if path, ok := lists.ParsePath(item); ok {
+		return path
+	}
+
+	return []int{lis.FindItem(strings.Trim(item, "\""))}
+}
+
+// findItem returns the index of an item in a list, given either its index or its value.
+// Returns -1 if the item can't be found.
+func findItem(lis *lists.ListtoList, item string) int {
+	item = strings.Trim(item, "\"")
+
+	i, err := strconv.Atoi(item)
+	if err != nil {
+		return lis.FindItem(item)
+	}
+
+	if i < 0 || i >= len(lis.List) {
+		return -1
+	}
+
+	return i
+}
+
+// assignInList assigns an item in the list to a user, or unassigns it if assignee is empty.
+func (b *bot) assignInList(c *caller, list, arg, assignee string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	item := strings.TrimSpace(mentionRegex.ReplaceAllString(arg, ""))
+	i := findItem(lis, item)
+	if i == -1 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, item),
+			Color:       yellow,
+		}
+	}
+
+	item = lis.Assign(i, assignee)
+	lis.MarkEdited(i, c.user, time.Now().Unix())
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if assignee == "" {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s in %s is no longer assigned to anyone", item, list),
+			Color:       green,
+		}
+	}
+
+	if lis.Notify && assignee != c.user {
+		b.notifyAssignee(assignee, c.user, item, list)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have assigned %s in %s to <@%s>", item, list, assignee),
+		Color:       green,
+	}
+}
+
+// tagInList adds tags to, or removes tags from, an item in the list.
+func (b *bot) tagInList(c *caller, list, arg string, add bool) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	item, tags := lists.ParseTags(arg)
+	if len(tags) == 0 {
+		return &discordgo.MessageEmbed{
+			Description: "You need to give me some tags, such as #MyTag",
+			Color:       yellow,
+		}
+	}
+
+	i := findItem(lis, item)
+	if i == -1 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, item),
+			Color:       yellow,
+		}
+	}
+
+	lis.MarkEdited(i, c.user, time.Now().Unix())
+
+	var action string
+	if add {
+		item = lis.AddTags(i, tags)
+		action = "tagged"
+	} else {
+		item = lis.RemoveTags(i, tags)
+		action = "untagged"
+	}
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have %s %s in %s with #%s", action, item, list, strings.Join(tags, " #")),
+		Color:       green,
+	}
+}
+
+// notifyAssignee sends a DM to a user letting them know they've been assigned an item.
+func (b *bot) notifyAssignee(assignee, user, item, list string) {
+	channel, err := b.Dgo.UserChannelCreate(assignee)
+	if err != nil {
+		fmt.Println("could not open DM for assignment", err)
+		return
+	}
+
+	_, err = b.Dgo.ChannelMessageSendEmbed(channel.ID, &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("<@%s> has assigned %s in %s to you", user, item, list),
+		Color:       blue,
+	})
+	if err != nil {
+		fmt.Println("could not send assignment DM", err)
+	}
+}
+
+// mine lists all items assigned to the user across the lists they can access.
+func (b *bot) mine(c *caller) *discordgo.MessageEmbed {
+	listtoLists, err := b.DDB.GetAllLists(c.ctx, c.guild, c.user)
+	if err != nil && err.Code != listtoErr.ListNotFound {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for _, lis := range listtoLists {
+		if !lis.VisibleIn(c.channel, c.category) {
+			continue
+		}
+
+		ok, err := b.canAccess(c, lis)
+		if err != nil {
+			err.LogError()
+			return storageErrMsg(err)
+		}
+		if !ok {
+			continue
+		}
+
+		var values string
+		for _, item := range lis.AssignedTo(c.user) {
+			values = fmt.Sprintf("%s\n%s", values, item.Value)
+		}
+
+		if values != "" {
+			fields = append(fields, &discordgo.MessageEmbedField{Name: lis.Name, Value: values})
+		}
+	}
+
+	if len(fields) == 0 {
+		return &discordgo.MessageEmbed{
+			Description: "Nothing has been assigned to you",
+			Color:       yellow,
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: "Here's everything assigned to you",
+		Color:       green,
+		Fields:      fields,
+	}
+}
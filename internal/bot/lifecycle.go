@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// begin marks the start of handling a command or background job, returning false if the bot is stopping.
+// Every call that returns true must be matched with b.inFlight.Done().
+func (b *bot) begin() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stopping {
+		return false
+	}
+
+	b.inFlight.Add(1)
+	return true
+}
+
+// Stop the bot from taking new commands, wait up to the timeout for those in progress to finish, then close the session.
+func (b *bot) Stop(timeout time.Duration) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("Stop")
+		}
+	}()
+
+	b.mu.Lock()
+	if b.stopping {
+		b.mu.Unlock()
+		return nil
+	}
+	b.stopping = true
+	close(b.stopped)
+	b.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		b.inFlight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(timeout):
+		fmt.Println("gave up waiting for commands to finish")
+	}
+
+	if b.Dgo != nil {
+		if err := b.Dgo.Close(); err != nil {
+			return listtoErr.ConvertError(err)
+		}
+	}
+
+	fmt.Println("The bot has gone to sleep...")
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+)
+
+// limits changes one of the server's limits, or shows them all if no limit is given.
+// Anybody can see the limits, but changing them needs the Manage Server permission.
+func (b *bot) limits(c *caller, limit, arg string) *discordgo.MessageEmbed {
+	settings, err := b.DDB.GetSettings(c.ctx, c.guild)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if limit == "" {
+		return &discordgo.MessageEmbed{
+			Description: "Here are the limits for lists here",
+			Color:       blue,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "lists", Value: strconv.Itoa(settings.Limits.MaxLists()), Inline: true},
+				{Name: "items", Value: strconv.Itoa(settings.Limits.MaxItems()), Inline: true},
+				{Name: "length", Value: strconv.Itoa(settings.Limits.MaxItemLength()), Inline: true},
+			},
+		}
+	}
+
+	if c.dm || !b.hasPermission(c, discordgo.PermissionManageServer) {
+		return &discordgo.MessageEmbed{
+			Description: "You need the Manage Server permission to change limits",
+			Color:       yellow,
+		}
+	}
+
+	// A limit set to default is stored as zero, which uses the default.
+	var value int
+	if strings.ToLower(arg) != "default" {
+		var err error
+		value, err = strconv.Atoi(arg)
+		if err != nil || value < 1 {
+			return &discordgo.MessageEmbed{
+				Description: "Limits need to be a positive number, or default",
+				Color:       yellow,
+			}
+		}
+	}
+
+	switch limit {
+	case "lists":
+		settings.Limits.Lists = value
+	case "items":
+		settings.Limits.Items = value
+	case "length":
+		if value > lists.MaxItemLength {
+			return &discordgo.MessageEmbed{
+				Description: fmt.Sprintf("Items can't be longer than %d characters, or they won't fit in a message", lists.MaxItemLength),
+				Color:       yellow,
+			}
+		}
+		settings.Limits.ItemLength = value
+	default:
+		return &discordgo.MessageEmbed{
+			Description: "I can limit the number of lists, the items in a list, or the length of items",
+			Color:       yellow,
+		}
+	}
+
+	if err := b.DDB.PutSettings(c.ctx, settings); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: "I couldn't update the limits",
+			Color:       red,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have updated the %s limit", limit),
+		Color:       green,
+	}
+}
+
+// checkNewList returns a message if a list can't be created or moved into its guild,
+// as the guild already has as many lists as it can, or the list doesn't fit the guild's limits.
+func (b *bot) checkNewList(ctx context.Context, lis *lists.ListtoList) *discordgo.MessageEmbed {
+	settings, err := b.DDB.GetSettings(ctx, lis.Guild)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	count, err := b.DDB.CountLists(ctx, lis.Guild)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if count >= settings.Limits.MaxLists() {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("There can only be %d lists here. Delete one to make room", settings.Limits.MaxLists()),
+			Color:       yellow,
+		}
+	}
+
+	return fitsLimits(settings.Limits, lis)
+}
+
+// checkListSize returns a message if a list's items don't fit its guild's limits.
+func (b *bot) checkListSize(ctx context.Context, lis *lists.ListtoList) *discordgo.MessageEmbed {
+	settings, err := b.DDB.GetSettings(ctx, lis.Guild)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	return fitsLimits(settings.Limits, lis)
+}
+
+// fitsLimits returns a message if a list holds too many items, or items that are too long.
+func fitsLimits(limits lists.Limits, lis *lists.ListtoList) *discordgo.MessageEmbed {
+	if lis.Size() > limits.MaxItems() {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s has %d items, but lists here can only hold %d", lis.Name, lis.Size(), limits.MaxItems()),
+			Color:       yellow,
+		}
+	}
+
+	if lis.LongestItem() > limits.MaxItemLength() {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s has items longer than the %d characters allowed here", lis.Name, limits.MaxItemLength()),
+			Color:       yellow,
+		}
+	}
+
+	return nil
+}
+
+// checkItem returns a message if a value is too long to go in a list,
+// or if a new item is being added to a list that is already full.
+func (b *bot) checkItem(ctx context.Context, lis *lists.ListtoList, value string, adding bool) *discordgo.MessageEmbed {
+	settings, err := b.DDB.GetSettings(ctx, lis.Guild)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if len([]rune(value)) > settings.Limits.MaxItemLength() {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Items can only be %d characters long", settings.Limits.MaxItemLength()),
+			Color:       yellow,
+		}
+	}
+
+	if adding && lis.Size() >= settings.Limits.MaxItems() {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is full, as lists can only hold %d items", lis.Name, settings.Limits.MaxItems()),
+			Color:       yellow,
+		}
+	}
+
+	return nil
+}
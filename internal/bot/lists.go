@@ -1,8 +1,10 @@
 package bot
 
 import (
+	"context"
 	"fmt"
-	"strconv"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
@@ -12,28 +14,28 @@ import (
 )
 
 // clearList wipes a list of it's values.
-func (b *bot) clearList(guild, list, user string, roles []string) *discordgo.MessageEmbed {
-	lis, err := b.DDB.GetList(guild, list)
-	if err != nil {
-		if err.Code == listtoErr.ListNotFound {
-			return noList(list)
-		}
-		err.LogError()
-		return failMsg()
+func (b *bot) clearList(c *caller, list string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
 	}
 
 	lis.Clear()
 
-	if err := b.DDB.PutList(lis); err != nil {
+	if err := b.putList(c.ctx, lis); err != nil {
 		err.LogError()
-		return &discordgo.MessageEmbed{
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
 			Description: fmt.Sprintf("I couldn't clear %s", list),
 			Color:       red,
-		}
+		})
 	}
 
 	return &discordgo.MessageEmbed{
@@ -43,19 +45,54 @@ func (b *bot) clearList(guild, list, user string, roles []string) *discordgo.Mes
 }
 
 // createList creates a new list.
-func (b *bot) createList(guild, list string, dm bool, access []string) *discordgo.MessageEmbed {
-	var lis *lists.ListtoList
-	if dm {
-		lis = lists.NewList(guild, list, lists.PersonalList)
-	} else if access != nil {
-		lis = lists.NewList(guild, list, lists.PrivateList)
-	} else {
-		lis = lists.NewList(guild, list, lists.PublicList)
+// A list can be bound to a channel or category, so that it can only be seen from there.
+func (b *bot) createList(c *caller, list string, lType lists.ListType, access []string, bind string) *discordgo.MessageEmbed {
+	if c.dm {
+		lType = lists.PersonalList
 	}
 
+	lis := lists.NewList(c.guild, list, lType)
+
 	lis.AddAccess(access)
+	lis.Channel = bind
+
+	return b.saveNewList(c.ctx, c.guild, list, lis)
+}
+
+// parseBinding reads a --here or --category flag given when creating a list,
+// returning the channel or category to bind the list to, and the rest of the arguments.
+func parseBinding(c *caller, arg string) (bind, rest string, msg *discordgo.MessageEmbed) {
+	var others []string
+	for _, a := range strings.Fields(arg) {
+		switch strings.ToLower(a) {
+		case "--here":
+			bind = c.channel
+		case "--category":
+			if c.category == "" {
+				return "", "", &discordgo.MessageEmbed{
+					Description: "This channel isn't in a category!",
+					Color:       yellow,
+				}
+			}
+			bind = c.category
+		default:
+			others = append(others, a)
+		}
+	}
+
+	if bind != "" && c.dm {
+		return "", "", &discordgo.MessageEmbed{
+			Description: "Personal lists can't be kept to one channel",
+			Color:       yellow,
+		}
+	}
+
+	return bind, strings.Join(others, " "), nil
+}
 
-	_, err := b.DDB.GetList(guild, list)
+// saveNewList stores a newly created list, as long as there isn't one with the same name.
+func (b *bot) saveNewList(ctx context.Context, guild, list string, lis *lists.ListtoList) *discordgo.MessageEmbed {
+	_, err := b.DDB.GetList(ctx, guild, list)
 	if err == nil {
 		return &discordgo.MessageEmbed{
 			Description: fmt.Sprintf("I found another list already called %s", list),
@@ -64,15 +101,19 @@ func (b *bot) createList(guild, list string, dm bool, access []string) *discordg
 	}
 	if err.Code != listtoErr.ListNotFound {
 		err.LogError()
-		return failMsg()
+		return storageErrMsg(err)
+	}
+
+	if msg := b.checkNewList(ctx, lis); msg != nil {
+		return msg
 	}
 
-	if err := b.DDB.PutList(lis); err != nil {
+	if err := b.putList(ctx, lis); err != nil {
 		err.LogError()
-		return &discordgo.MessageEmbed{
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
 			Description: fmt.Sprintf("I couldn't create a list called %s", list),
 			Color:       red,
-		}
+		})
 	}
 
 	return &discordgo.MessageEmbed{
@@ -82,25 +123,51 @@ func (b *bot) createList(guild, list string, dm bool, access []string) *discordg
 }
 
 // deleteList deletes a list.
-func (b *bot) deleteList(guild, list, user string, roles []string) *discordgo.MessageEmbed {
-	lis, msg := b.getDDBList(guild, list, user)
+func (b *bot) deleteList(c *caller, list string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
 	if msg != nil {
 		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
 	}
 
-	err := b.DDB.DeleteList(guild, list, user)
+	if lis.Type == lists.PersonalList && lis.Guild != c.user {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Only the owner of %s can delete it", list),
+			Color:       yellow,
+		}
+	}
+
+	ok, err := b.canDelete(c, lis)
 	if err != nil {
-		fmt.Println("failed to delete item", err)
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if !ok {
 		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("You need the Manage Messages permission to delete %s", list),
+			Color:       yellow,
+		}
+	}
+
+	err = b.DDB.DeleteList(c.ctx, lis.Guild, list, lis.Guild)
+	if err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
 			Description: fmt.Sprintf("I couldn't delete %s", list),
 			Color:       red,
-		}
+		})
 	}
 
+	b.retirePin(lis, "This list has been deleted")
+
 	return &discordgo.MessageEmbed{
 		Description: fmt.Sprintf("I have deleted %s", list),
 		Color:       green,
@@ -108,57 +175,68 @@ func (b *bot) deleteList(guild, list, user string, roles []string) *discordgo.Me
 }
 
 // getList gets a list.
-func (b *bot) getList(guild, list, arg, user string, roles []string) *discordgo.MessageEmbed {
-	lis, msg := b.getDDBList(guild, list, user)
+func (b *bot) getList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
 	if msg != nil {
 		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
 	}
 
-	var fields []*discordgo.MessageEmbedField
-
-	var values, desc string
-	if arg == "" {
-		desc = "Your List"
-		for _, l := range lis.List {
-			if len(values)+len(l.Value) > 1024 {
-				fields = append(fields, &discordgo.MessageEmbedField{Name: list, Value: values})
-				list = l.Value
-				values = ""
-				continue
+	path, isPath := lists.ParsePath(arg)
+	if !isPath {
+		filter, ok := parseListFilter(arg)
+		if !ok {
+			return &discordgo.MessageEmbed{
+				Description: "The searched item needs to be a number! Otherwise you can filter by #tags, by:@user, or use -v for more detail",
+				Color:       yellow,
 			}
-			values = fmt.Sprintf("%s\n%s", values, l.Value)
 		}
 
-		if values == "" {
-			values = "This list is empty!"
+		return listEmbed(lis, filter)
+	}
+
+	item := lis.Item(path)
+	if item == nil {
+		return &discordgo.MessageEmbed{
+			Description: "I couldn't find an item at that position!",
+			Color:       yellow,
 		}
+	}
 
-		fields = append(fields, &discordgo.MessageEmbedField{Name: list, Value: values})
+	values := strings.Join(append([]string{item.Value}, itemLines(item.Children, 1, false)...), "\n")
 
-		fields = append(fields, &discordgo.MessageEmbedField{Name: "List Entries", Value: fmt.Sprintf("%d", len(lis.List))})
-	} else {
-		desc = "Your Item"
-		i, err := strconv.Atoi(arg)
-		if err != nil {
-			return &discordgo.MessageEmbed{
-				Description: "The searched item needs to be a number!",
-				Color:       yellow,
-			}
-		}
+	return &discordgo.MessageEmbed{
+		Description: "Your Item",
+		Color:       green,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: fmt.Sprintf("Item at position %s", arg), Value: values},
+		},
+	}
+}
 
-		values = lis.SelectItem(i)
-		if values == "" {
-			return &discordgo.MessageEmbed{
-				Description: "I couldn't find an item at that position!",
-				Color:       yellow,
-			}
-		}
+// listEmbed shows the items in a list that match the filter.
+func listEmbed(lis *lists.ListtoList, filter listFilter) *discordgo.MessageEmbed {
+	desc := "Your List"
+	if lis.Locked {
+		desc = "Your List 🔒"
+	}
+	items := lis.List
+	if filter.filtered() {
+		desc = fmt.Sprintf("%s, showing only matching items", desc)
+		items = lis.Filter(filter.tags, filter.addedBy)
+	}
 
-		fields = append(fields, &discordgo.MessageEmbedField{Name: fmt.Sprintf("Item at position %d", i), Value: values})
+	fields := itemFields(lis.Name, items, filter.verbose)
+
+	fields = append(fields, &discordgo.MessageEmbedField{Name: "List Entries", Value: fmt.Sprintf("%d", len(items))})
+
+	if !filter.filtered() {
+		if summary := tagSummary(lis); summary != "" {
+			fields = append(fields, &discordgo.MessageEmbedField{Name: "Tags", Value: summary})
+		}
 	}
 
 	return &discordgo.MessageEmbed{
@@ -168,58 +246,238 @@ func (b *bot) getList(guild, list, arg, user string, roles []string) *discordgo.
 	}
 }
 
-// listLists prints a list of lists on the server.
-func (b *bot) listLists(guild, user string, roles []string) *discordgo.MessageEmbed {
-	listtoLists, err := b.DDB.GetAllLists(guild, user)
-	if err != nil {
-		if err.Code == listtoErr.ListNotFound {
-			return &discordgo.MessageEmbed{
-				Description: "I couldn't find any lists for you",
-				Color:       yellow,
-			}
+// itemLines prints list items one per line, with any nested items indented underneath them.
+func itemLines(items []lists.ListItem, depth int, verbose bool) []string {
+	var lines []string
+	for _, l := range items {
+		label := l.Label()
+		if depth > 0 {
+			label = fmt.Sprintf("%s└ %s", strings.Repeat("\u2003", depth-1), label)
 		}
+		if verbose {
+			label = fmt.Sprintf("%s\n%s", label, history(l))
+		}
+
+		lines = append(lines, label)
+		lines = append(lines, itemLines(l.Children, depth+1, verbose)...)
+	}
+
+	return lines
+}
+
+// listFilter holds the options given when getting a whole list.
+type listFilter struct {
+	tags    []string
+	addedBy string
+	verbose bool
+}
+
+// filtered returns if the listFilter hides any items.
+func (f listFilter) filtered() bool {
+	return len(f.tags) != 0 || f.addedBy != ""
+}
+
+// authorRegex matches a filter on who added an item, such as by:@user.
+var authorRegex = regexp.MustCompile(`^by:<@!?(\d+)>$`)
+
+// parseListFilter reads the options given when getting a list, returning false if any aren't recognised.
+func parseListFilter(arg string) (f listFilter, ok bool) {
+	for _, a := range strings.Fields(arg) {
+		switch {
+		case a == "-v" || a == "--verbose":
+			f.verbose = true
+		case authorRegex.MatchString(a):
+			f.addedBy = authorRegex.FindStringSubmatch(a)[1]
+		case lists.IsTag(a):
+			_, tags := lists.ParseTags(a)
+			f.tags = append(f.tags, tags...)
+		default:
+			return f, false
+		}
+	}
+
+	return f, true
+}
+
+const (
+	// maxFieldLength is the most characters Discord allows in an embed field's value.
+	maxFieldLength = 1024
+
+	// maxItemsLength is how many characters of items are shown, leaving room in an embed's 6000 characters for everything else.
+	maxItemsLength = 4000
+)
+
+// itemFields lays out list items as embed fields, splitting them when they won't fit in one.
+// Items past what fits in an embed are counted rather than shown.
+// Verbose fields include who added and last edited each item.
+func itemFields(name string, items []lists.ListItem, verbose bool) []*discordgo.MessageEmbedField {
+	var fields []*discordgo.MessageEmbedField
+
+	var values string
+	var total int
+	add := func(line string) {
+		if len(values)+len(line)+1 > maxFieldLength {
+			fields = append(fields, &discordgo.MessageEmbedField{Name: name, Value: values})
+			name = "(continued)"
+			values = ""
+		}
+		values = fmt.Sprintf("%s\n%s", values, line)
+		total += len(line) + 1
+	}
+
+	lines := itemLines(items, 0, verbose)
+	for i, label := range lines {
+		label = truncate(label, maxFieldLength-1)
+		if total+len(label) > maxItemsLength {
+			add(fmt.Sprintf("...and %d more", len(lines)-i))
+			break
+		}
+		add(label)
+	}
+
+	if values == "" {
+		values = "This list is empty!"
+	}
+
+	return append(fields, &discordgo.MessageEmbedField{Name: name, Value: values})
+}
+
+// truncate shortens a value to at most max bytes, marking that it has been cut.
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+
+	runes := []rune(value)
+	for len(string(runes))+len("…") > max {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + "…"
+}
+
+// history prints who added and last edited an item, and when.
+func history(item lists.ListItem) string {
+	history := "> added"
+	if item.AddedBy != "" {
+		history = fmt.Sprintf("%s by <@%s>", history, item.AddedBy)
+	}
+	if item.TimeAdded != 0 {
+		history = fmt.Sprintf("%s on %s", history, formatTime(item.TimeAdded))
+	}
+
+	if item.EditedBy != "" {
+		history = fmt.Sprintf("%s, edited by <@%s> on %s", history, item.EditedBy, formatTime(item.TimeEdited))
+	}
+
+	return history
+}
+
+// tagSummary prints each tag used in a list with how many items have it.
+func tagSummary(lis *lists.ListtoList) string {
+	counts := lis.TagCounts()
+
+	tags := make([]string, 0, len(counts))
+	for t := range counts {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+
+	lines := make([]string, len(tags))
+	for i, t := range tags {
+		lines[i] = fmt.Sprintf("#%s (%d)", t, counts[t])
+	}
+
+	return truncateLines(lines)
+}
+
+// listLists prints a list of lists on the server, and personal lists shared with the user.
+func (b *bot) listLists(c *caller) *discordgo.MessageEmbed {
+	listtoLists, err := b.DDB.GetAllLists(c.ctx, c.guild, c.user)
+	if err != nil && err.Code != listtoErr.ListNotFound {
 		err.LogError()
-		return failMsg()
+		return storageErrMsg(err)
+	}
+
+	joined, err := b.joinedLists(c)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
 	}
 
 	var values string
 	for _, lis := range listtoLists {
-		if lis.CanAccess(user, roles) {
+		if !lis.VisibleIn(c.channel, c.category) {
+			continue
+		}
+
+		ok, err := b.canAccess(c, lis)
+		if err != nil {
+			err.LogError()
+			return storageErrMsg(err)
+		}
+		if ok {
 			values = fmt.Sprintf("%s\n%s", values, lis.Name)
 		}
 	}
 
+	var shared string
+	for _, lis := range joined {
+		ok, err := b.canAccess(c, lis)
+		if err != nil {
+			err.LogError()
+			return storageErrMsg(err)
+		}
+		if ok {
+			shared = fmt.Sprintf("%s\n%s", shared, lis.Name)
+		}
+	}
+
+	if values == "" && shared == "" {
+		return &discordgo.MessageEmbed{
+			Description: "I couldn't find any lists for you",
+			Color:       yellow,
+		}
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	if values != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Your lists", Value: values})
+	}
+	if shared != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Shared with you", Value: shared})
+	}
+
 	return &discordgo.MessageEmbed{
 		Description: "I found these lists!",
 		Color:       green,
-		Fields: []*discordgo.MessageEmbedField{
-			{
-				Name:  "Your lists",
-				Value: values,
-			},
-		},
+		Fields:      fields,
 	}
 }
 
 // addAccessToList adds the supplied users and roles to the allowed users on a list.
-func (b *bot) addAccessToList(guild, list string, access []string, user string, roles []string) *discordgo.MessageEmbed {
-	lis, msg := b.getDDBList(guild, list, user)
+func (b *bot) addAccessToList(c *caller, list string, access []string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
 	if msg != nil {
 		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
 	}
 
 	lis.AddAccess(access)
 
-	if err := b.DDB.PutList(lis); err != nil {
+	if err := b.putList(c.ctx, lis); err != nil {
 		err.LogError()
-		return &discordgo.MessageEmbed{
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
 			Description: fmt.Sprintf("I couldn't update the permissions for %s", list),
 			Color:       red,
-		}
+		})
 	}
 
 	return &discordgo.MessageEmbed{
@@ -228,56 +486,124 @@ func (b *bot) addAccessToList(guild, list string, access []string, user string,
 	}
 }
 
-func (b *bot) removeAccessFromList(guild, list string, access []string, user string, roles []string) *discordgo.MessageEmbed {
-	lis, msg := b.getDDBList(guild, list, user)
+func (b *bot) removeAccessFromList(c *caller, list string, access []string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
 	if msg != nil {
 		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
 	}
 
-	lis.RemoveAccess(access)
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
 
-	if err := b.DDB.PutList(lis); err != nil {
-		err.LogError()
+	removed := lis.RemoveAccess(access)
+	if len(removed) == 0 {
 		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I didn't remove anybody from %s. Only private lists have allowed users, and they always keep at least one", list),
+			Color:       yellow,
+		}
+	}
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
 			Description: fmt.Sprintf("I couldn't update the permissions for %s", list),
 			Color:       red,
+		})
+	}
+
+	desc := fmt.Sprintf("I have removed %s from allowed users on %s", b.mentions(lis.Guild, removed), list)
+	if kept := len(access) - len(removed); kept > 0 {
+		desc = fmt.Sprintf("%s. I kept %d that either weren't allowed already, or were the last allowed on the list", desc, kept)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: desc,
+		Color:       green,
+	}
+}
+
+// lockList locks or unlocks a list, so nobody can change it while it is locked.
+func (b *bot) lockList(c *caller, list string, lock bool) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	ok, err := b.canLock(c, lis)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if !ok {
+		return noPerms(list)
+	}
+
+	state := "unlocked"
+	if lock {
+		state = "locked"
+	}
+
+	if lis.Locked == lock {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is already %s", list, state),
+			Color:       yellow,
 		}
 	}
 
+	lis.Locked = lock
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't update %s", list),
+			Color:       red,
+		})
+	}
+
 	return &discordgo.MessageEmbed{
-		Description: fmt.Sprintf("I have removed those tags from allowed users on %s", list),
+		Description: fmt.Sprintf("I have %s %s", state, list),
 		Color:       green,
 	}
 }
 
 // sortList sorts the list.
-func (b *bot) sortList(guild, list, arg, user string, roles []string) *discordgo.MessageEmbed {
-	lis, msg := b.getDDBList(guild, list, user)
+func (b *bot) sortList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
 	if msg != nil {
 		return msg
 	}
 
-	if !lis.CanAccess(user, roles) {
-		return noPerms(list)
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
 	}
 
 	sort := strings.ToLower(arg)
-	if sort != "name" && sort != "time" {
+	if sort != "name" && sort != "time" && sort != "votes" {
 		return &discordgo.MessageEmbed{
-			Description: "Sorry! I only sort by \"name\" or \"time\"!",
+			Description: "Sorry! I only sort by \"name\", \"time\" or \"votes\"!",
 			Color:       yellow,
 		}
 	}
 
 	lis.Sort(sort)
 
-	if err := b.DDB.PutList(lis); err != nil {
+	if err := b.putList(c.ctx, lis); err != nil {
 		err.LogError()
-		return failMsg()
+		return storageErrMsg(err)
 	}
 
 	return &discordgo.MessageEmbed{
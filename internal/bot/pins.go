@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// pinList posts a message showing the list, which is updated whenever the list changes.
+func (b *bot) pinList(c *caller, list string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	message, err := b.Dgo.ChannelMessageSendEmbed(c.channel, pinEmbed(lis))
+	if err != nil {
+		fmt.Println("failed to send pinned list", err)
+		return failMsg()
+	}
+
+	old := *lis
+	lis.Pin = &lists.Pin{Channel: c.channel, Message: message.ID}
+
+	if err := b.DDB.PutList(c.ctx, lis); err != nil {
+		err.LogError()
+		_ = b.Dgo.ChannelMessageDelete(c.channel, message.ID)
+		return storageErrMsg(err)
+	}
+
+	// A list only has one pinned message, so the old one would otherwise show the list as it was forever.
+	b.retirePin(&old, "This list has been pinned somewhere else")
+
+	return nil
+}
+
+// unpinList stops updating the list's pinned message.
+func (b *bot) unpinList(c *caller, list string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	if lis.Pin == nil {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s isn't pinned anywhere", list),
+			Color:       yellow,
+		}
+	}
+
+	lis.Pin = nil
+
+	if err := b.DDB.PutList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I'll stop updating the pinned message for %s", list),
+		Color:       green,
+	}
+}
+
+// putList stores a list, then updates its pinned message if it has one.
+func (b *bot) putList(ctx context.Context, lis *lists.ListtoList) *listtoErr.ListtoError {
+	if err := b.DDB.PutList(ctx, lis); err != nil {
+		return err
+	}
+
+	b.refreshPin(ctx, lis)
+
+	return nil
+}
+
+// refreshPin updates the pinned message for a list to show its current state.
+func (b *bot) refreshPin(ctx context.Context, lis *lists.ListtoList) {
+	if lis.Pin == nil {
+		return
+	}
+
+	_, err := b.Dgo.ChannelMessageEditEmbed(lis.Pin.Channel, lis.Pin.Message, pinEmbed(lis))
+	if err == nil {
+		return
+	}
+
+	// The message has been deleted, so there's nothing left to update.
+	if notFound(err) {
+		lis.Pin = nil
+		if err := b.DDB.PutList(ctx, lis); err != nil {
+			err.LogError()
+		}
+		return
+	}
+
+	fmt.Println("failed to update pinned list", err)
+}
+
+// pinEmbed shows a whole list, along with when it was last updated.
+func pinEmbed(lis *lists.ListtoList) *discordgo.MessageEmbed {
+	embed := listEmbed(lis, listFilter{})
+	embed.Title = lis.Name
+	embed.Description = "This message is kept up to date as the list changes"
+	embed.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	return embed
+}
+
+// retirePin replaces a list's pinned message with a note saying why it will no longer be updated.
+func (b *bot) retirePin(lis *lists.ListtoList, reason string) {
+	if lis.Pin == nil {
+		return
+	}
+
+	_, err := b.Dgo.ChannelMessageEditEmbed(lis.Pin.Channel, lis.Pin.Message, &discordgo.MessageEmbed{
+		Title:       lis.Name,
+		Description: reason,
+		Color:       yellow,
+	})
+	if err != nil {
+		fmt.Println("failed to update pinned list", err)
+	}
+
+	lis.Pin = nil
+}
@@ -0,0 +1,289 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// defaultPollDuration is how long a poll runs for if no duration is given.
+const defaultPollDuration = time.Hour
+
+// pollList posts a poll on the items in a list, which closes after the given duration.
+// Adding "reorder" sorts the list by the results when the poll closes.
+func (b *bot) pollList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	duration, reorder := defaultPollDuration, false
+	for _, a := range strings.Fields(strings.ToLower(arg)) {
+		if a == "reorder" {
+			reorder = true
+			continue
+		}
+
+		d, ok := parseDuration(a)
+		if !ok {
+			return &discordgo.MessageEmbed{
+				Description: "Poll durations need to look like 30m, 2h or 1d",
+				Color:       yellow,
+			}
+		}
+		duration = d
+	}
+
+	if len(lis.List) < 2 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s needs at least two items to hold a poll", list),
+			Color:       yellow,
+		}
+	}
+
+	_, err := b.DDB.GetPoll(c.ctx, lis.Guild, lis.Name)
+	if err == nil {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("There's already a poll running for %s", list),
+			Color:       yellow,
+		}
+	}
+	if err.Code != listtoErr.PollNotFound {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	poll := &lists.Poll{
+		Guild:   lis.Guild,
+		Name:    lis.Name,
+		Channel: c.channel,
+		Ends:    time.Now().Add(duration).Unix(),
+		Reorder: reorder,
+	}
+	for i, l := range lis.List {
+		if i == len(numberEmojis) {
+			break
+		}
+		poll.Options = append(poll.Options, l.Value)
+	}
+
+	message, sendErr := b.Dgo.ChannelMessageSendEmbed(c.channel, pollEmbed(poll))
+	if sendErr != nil {
+		fmt.Println("failed to send poll", sendErr)
+		return failMsg()
+	}
+	poll.Message = message.ID
+
+	if err := b.putPoll(c.ctx, poll); err != nil {
+		err.LogError()
+		_ = b.Dgo.ChannelMessageDelete(c.channel, message.ID)
+		return storageErrMsg(err)
+	}
+
+	for i := range poll.Options {
+		if err := b.Dgo.MessageReactionAdd(c.channel, message.ID, numberEmojis[i]); err != nil {
+			fmt.Println("failed to add reaction", err)
+			break
+		}
+	}
+
+	return nil
+}
+
+// pollEmbed shows a poll's options numbered to match the reactions on it.
+func pollEmbed(poll *lists.Poll) *discordgo.MessageEmbed {
+	var values string
+	for i, o := range poll.Options {
+		values = fmt.Sprintf("%s\n%s %s", values, numberEmojis[i], o)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Poll: %s", poll.Name),
+		Description: "React to vote for as many items as you like!",
+		Color:       blue,
+		Fields:      []*discordgo.MessageEmbedField{{Name: "Options", Value: values}},
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Poll closes"},
+		Timestamp:   time.Unix(poll.Ends, 0).UTC().Format(time.RFC3339),
+	}
+}
+
+// pollTimes scans for when every poll ends.
+func (b *bot) pollTimes(ctx context.Context) (map[dueKey]int64, *listtoErr.ListtoError) {
+	polls, err := b.DDB.GetAllPolls(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make(map[dueKey]int64, len(polls))
+	for _, p := range polls {
+		times[dueKey{guild: p.Guild, name: p.Name}] = p.Ends
+	}
+
+	return times, nil
+}
+
+// checkPoll reads a poll that looks due, closing it if it still is.
+func (b *bot) checkPoll(ctx context.Context, k dueKey, now time.Time) {
+	poll, err := b.DDB.GetPoll(ctx, k.guild, k.name)
+	if err != nil {
+		if err.Code == listtoErr.PollNotFound {
+			b.polls.remove(k.guild, k.name)
+			return
+		}
+		err.LogError()
+		return
+	}
+
+	if !poll.Due(now) {
+		b.polls.set(poll.Guild, poll.Name, poll.Ends)
+		return
+	}
+
+	b.closePoll(ctx, poll)
+}
+
+// closePoll counts the votes on a poll and announces the winner, reordering the list if asked.
+// The poll is only deleted once the results are out, so a poll that fails to close is tried again next time.
+func (b *bot) closePoll(ctx context.Context, poll *lists.Poll) {
+	message, err := b.Dgo.ChannelMessage(poll.Channel, poll.Message)
+	if err != nil {
+		// The poll message has been deleted, so there are no votes left to count.
+		if notFound(err) {
+			b.deletePoll(ctx, poll)
+			return
+		}
+		fmt.Println("failed to get poll message", err)
+		return
+	}
+
+	votes := make([]int, len(poll.Options))
+	for _, r := range message.Reactions {
+		for i, e := range numberEmojis[:len(poll.Options)] {
+			if r.Emoji != nil && r.Emoji.Name == e {
+				votes[i] = r.Count
+				// Don't count the reaction added to vote with.
+				if r.Me {
+					votes[i]--
+				}
+			}
+		}
+	}
+
+	results := poll.Results(votes)
+
+	if poll.Reorder {
+		b.reorderByPoll(ctx, poll, results)
+	}
+
+	if _, err := b.Dgo.ChannelMessageSendEmbed(poll.Channel, pollResultsEmbed(poll, results)); err != nil && !notFound(err) {
+		fmt.Println("failed to send poll results", err)
+		return
+	}
+
+	b.deletePoll(ctx, poll)
+}
+
+// putPoll stores a poll, and when it ends.
+func (b *bot) putPoll(ctx context.Context, poll *lists.Poll) *listtoErr.ListtoError {
+	if err := b.DDB.PutPoll(ctx, poll); err != nil {
+		return err
+	}
+
+	b.polls.set(poll.Guild, poll.Name, poll.Ends)
+	return nil
+}
+
+// deletePoll deletes a poll that has closed.
+func (b *bot) deletePoll(ctx context.Context, poll *lists.Poll) {
+	if err := b.DDB.DeletePoll(ctx, poll.Guild, poll.Name); err != nil {
+		err.LogError()
+		return
+	}
+
+	b.polls.remove(poll.Guild, poll.Name)
+}
+
+// reorderByPoll sorts a list to match a poll's results.
+func (b *bot) reorderByPoll(ctx context.Context, poll *lists.Poll, results []lists.PollResult) {
+	lis, err := b.DDB.GetList(ctx, poll.Guild, poll.Name)
+	if err != nil {
+		err.LogError()
+		return
+	}
+
+	if lis.Locked {
+		return
+	}
+
+	values := make([]string, len(results))
+	for i, r := range results {
+		values[i] = r.Value
+	}
+
+	lis.Reorder(values)
+
+	if err := b.putList(ctx, lis); err != nil {
+		err.LogError()
+	}
+}
+
+// pollResultsEmbed announces the winner of a poll, along with how every option did.
+func pollResultsEmbed(poll *lists.Poll, results []lists.PollResult) *discordgo.MessageEmbed {
+	var winners, lines []string
+	for i, r := range results {
+		if r.Votes > 0 && r.Votes == results[0].Votes {
+			winners = append(winners, r.Value)
+		}
+		lines = append(lines, fmt.Sprintf("%d. %s - %d", i+1, r.Value, r.Votes))
+	}
+
+	var desc string
+	switch len(winners) {
+	case 0:
+		desc = "Nobody voted, so there's no winner"
+	case 1:
+		desc = fmt.Sprintf("The winner is %s with %d votes!", winners[0], results[0].Votes)
+	default:
+		desc = fmt.Sprintf("It's a tie between %s with %d votes each!", strings.Join(winners, ", "), results[0].Votes)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("The poll for %s has closed", poll.Name),
+		Description: desc,
+		Color:       green,
+		Fields:      []*discordgo.MessageEmbedField{{Name: "Results", Value: truncateLines(lines)}},
+	}
+}
+
+// parseDuration reads a duration such as 30m or 2h, also accepting days such as 1d.
+func parseDuration(value string) (time.Duration, bool) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || days < 1 {
+			return 0, false
+		}
+		return time.Duration(days) * 24 * time.Hour, true
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < time.Minute {
+		return 0, false
+	}
+
+	return d, true
+}
@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// Each user can send a burst of commands, then one every couple of seconds.
+	userRate  = 0.5
+	userBurst = 5
+
+	// Each guild can send more, to allow for several users at once.
+	guildRate  = 2
+	guildBurst = 20
+
+	// maxBuckets is how many buckets a limiter keeps before forgetting those that have refilled.
+	maxBuckets = 10000
+)
+
+// bucket holds the tokens left for one user or guild.
+type bucket struct {
+	tokens   float64
+	last     time.Time
+	notified bool
+}
+
+// limiter is a token bucket rate limiter, with a bucket for each key.
+type limiter struct {
+	sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+}
+
+// newLimiter creates a limiter that refills rate tokens a second, up to burst.
+func newLimiter(rate float64, burst int) *limiter {
+	return &limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow takes a token for the key, returning false with how long until the next token if there are none.
+// notify is only true the first time a key is limited, until it is allowed again, so users are only told once.
+func (l *limiter) allow(key string, now time.Time) (ok bool, wait time.Duration, notify bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	b := l.refill(key, now)
+
+	if b.tokens < 1 {
+		notify = !b.notified
+		b.notified = true
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second)), notify
+	}
+
+	b.tokens--
+	b.notified = false
+	return true, 0, false
+}
+
+// take takes a token for the key, returning false if there are none.
+// Unlike allow it never notifies, so it doesn't stop the user being told when a command is limited.
+func (l *limiter) take(key string, now time.Time) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	b := l.refill(key, now)
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// refill returns the bucket for the key, topped up with the tokens gained since it was last used.
+func (l *limiter) refill(key string, now time.Time) *bucket {
+	b, found := l.buckets[key]
+	if !found {
+		if len(l.buckets) >= maxBuckets {
+			l.prune(now)
+		}
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	return b
+}
+
+// prune forgets the buckets that would have refilled, as they are the same as new ones.
+func (l *limiter) prune(now time.Time) {
+	for k, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// rateLimited returns if a command should be ignored because the user or guild has sent too many,
+// telling them once how long to wait.
+func (b *bot) rateLimited(s *discordgo.Session, m *discordgo.MessageCreate) bool {
+	now := time.Now()
+
+	ok, wait, notify := b.userLimit.allow(m.Author.ID, now)
+	if ok && m.GuildID != "" {
+		ok, wait, notify = b.guildLimit.allow(m.GuildID, now)
+	}
+	if ok {
+		return false
+	}
+
+	if notify {
+		_, err := s.ChannelMessageSendEmbed(m.ChannelID, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Slow down! You can use me again in %d seconds", int(math.Ceil(wait.Seconds()))),
+			Color:       yellow,
+		})
+		if err != nil {
+			fmt.Println("failed to send cooldown notice", err)
+		}
+	}
+
+	return true
+}
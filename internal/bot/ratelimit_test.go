@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	start := time.Unix(0, 0)
+
+	type call struct {
+		key    string
+		at     time.Duration
+		ok     bool
+		notify bool
+	}
+
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		calls []call
+	}{
+		{
+			name:  "allows a burst",
+			rate:  1,
+			burst: 2,
+			calls: []call{
+				{key: "a", ok: true},
+				{key: "a", ok: true},
+				{key: "a", ok: false, notify: true},
+			},
+		},
+		{
+			name:  "only notifies once while limited",
+			rate:  1,
+			burst: 1,
+			calls: []call{
+				{key: "a", ok: true},
+				{key: "a", ok: false, notify: true},
+				{key: "a", ok: false},
+			},
+		},
+		{
+			name:  "refills over time",
+			rate:  0.5,
+			burst: 1,
+			calls: []call{
+				{key: "a", ok: true},
+				{key: "a", at: time.Second, ok: false, notify: true},
+				{key: "a", at: 2 * time.Second, ok: true},
+				{key: "a", at: 2 * time.Second, ok: false, notify: true},
+			},
+		},
+		{
+			name:  "keeps keys apart",
+			rate:  1,
+			burst: 1,
+			calls: []call{
+				{key: "a", ok: true},
+				{key: "a", ok: false, notify: true},
+				{key: "b", ok: true},
+			},
+		},
+		{
+			name:  "doesn't refill past the burst",
+			rate:  1,
+			burst: 2,
+			calls: []call{
+				{key: "a", ok: true},
+				{key: "a", at: time.Hour, ok: true},
+				{key: "a", at: time.Hour, ok: true},
+				{key: "a", at: time.Hour, ok: false, notify: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter(tt.rate, tt.burst)
+			for i, c := range tt.calls {
+				ok, wait, notify := l.allow(c.key, start.Add(c.at))
+				if ok != c.ok || notify != c.notify {
+					t.Fatalf("call %d: allow() = %t, %t, want %t, %t", i, ok, notify, c.ok, c.notify)
+				}
+				if !ok && wait <= 0 {
+					t.Fatalf("call %d: limited with a wait of %s", i, wait)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterTake(t *testing.T) {
+	start := time.Unix(0, 0)
+	l := newLimiter(1, 1)
+
+	if !l.take("a", start) {
+		t.Fatal("take() = false for a full bucket")
+	}
+	if l.take("a", start) {
+		t.Fatal("take() = true for an empty bucket")
+	}
+
+	// Taking without a token mustn't use up the notice for the next limited command.
+	if ok, _, notify := l.allow("a", start); ok || !notify {
+		t.Fatalf("allow() = %t, %t, want false, true", ok, notify)
+	}
+}
@@ -0,0 +1,251 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+)
+
+const (
+	doneMode   = "done"
+	removeMode = "remove"
+	voteMode   = "vote"
+)
+
+// reactionCacheSize is how many recent messages in each channel are cached to look reactions up in.
+const reactionCacheSize = 50
+
+// numberEmojis are the reactions used to pick items, in order.
+var numberEmojis = []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
+
+// reactFooterRegex matches the footer of a message that can be reacted to, giving the mode and list name.
+// Keeping these in the footer means reactions still work after a restart, without storing every message.
+var reactFooterRegex = regexp.MustCompile(`^React to (\w+) items in (\S+)$`)
+
+// reactList posts a list with numbered reactions, which act on the items when clicked.
+func (b *bot) reactList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	mode := strings.ToLower(arg)
+	if mode == "" {
+		mode = doneMode
+	}
+
+	if mode != doneMode && mode != removeMode && mode != voteMode {
+		return &discordgo.MessageEmbed{
+			Description: "Reactions can either mark items as done, remove them, or vote for them",
+			Color:       yellow,
+		}
+	}
+
+	message, err := b.Dgo.ChannelMessageSendEmbed(c.channel, reactEmbed(lis, mode))
+	if err != nil {
+		fmt.Println("failed to send reaction list", err)
+		return failMsg()
+	}
+
+	for i := range lis.List {
+		if i == len(numberEmojis) {
+			break
+		}
+		if err := b.Dgo.MessageReactionAdd(c.channel, message.ID, numberEmojis[i]); err != nil {
+			fmt.Println("failed to add reaction", err)
+			break
+		}
+	}
+
+	return nil
+}
+
+// reactEmbed shows a list numbered to match the reactions on it.
+func reactEmbed(lis *lists.ListtoList, mode string) *discordgo.MessageEmbed {
+	var values string
+	for i, l := range lis.List {
+		if i == len(numberEmojis) {
+			values = fmt.Sprintf("%s\n...and %d more", values, len(lis.List)-i)
+			break
+		}
+		values = fmt.Sprintf("%s\n%s %s", values, numberEmojis[i], l.Label())
+	}
+
+	if values == "" {
+		values = "This list is empty!"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:  lis.Name,
+		Color:  green,
+		Fields: []*discordgo.MessageEmbedField{{Name: "Items", Value: values}},
+		Footer: &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("React to %s items in %s", mode, lis.Name)},
+	}
+}
+
+// shownItem returns the label a reaction list showed for the item at an index, or an empty string if there wasn't one.
+func shownItem(embed *discordgo.MessageEmbed, index int) string {
+	if len(embed.Fields) == 0 {
+		return ""
+	}
+
+	prefix := numberEmojis[index] + " "
+	for _, line := range strings.Split(embed.Fields[0].Value, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimPrefix(line, prefix)
+		}
+	}
+
+	return ""
+}
+
+// reactionHandler returns a handlerfunc for reactions on lists posted by reactList.
+func (b *bot) reactionHandler() func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	return func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+		if r.UserID == b.BotID {
+			return
+		}
+
+		if !b.begin() {
+			return
+		}
+		defer b.inFlight.Done()
+
+		index := -1
+		for i, e := range numberEmojis {
+			if e == r.Emoji.Name {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			return
+		}
+
+		// Most reactions are on messages that aren't reaction lists, such as polls, so check the cache before asking Discord.
+		message, err := s.State.Message(r.ChannelID, r.MessageID)
+		if err != nil {
+			message, err = s.ChannelMessage(r.ChannelID, r.MessageID)
+		}
+		if err != nil {
+			fmt.Println("failed to get reacted message", err)
+			return
+		}
+
+		if message.Author == nil || message.Author.ID != b.BotID || len(message.Embeds) == 0 || message.Embeds[0].Footer == nil {
+			return
+		}
+
+		match := reactFooterRegex.FindStringSubmatch(message.Embeds[0].Footer.Text)
+		if match == nil {
+			return
+		}
+		mode, list := match[1], match[2]
+
+		// Reactions are limited along with commands, but as they are easy to spam they are dropped without a notice.
+		now := time.Now()
+		if !b.userLimit.take(r.UserID, now) {
+			return
+		}
+		if r.GuildID != "" && !b.guildLimit.take(r.GuildID, now) {
+			return
+		}
+
+		// Let the same reaction be used again.
+		defer func() {
+			if err := s.MessageReactionRemove(r.ChannelID, r.MessageID, r.Emoji.Name, r.UserID); err != nil {
+				fmt.Println("failed to remove reaction", err)
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+
+		c := &caller{ctx: ctx, guild: r.GuildID, channel: r.ChannelID, user: r.UserID}
+		if c.guild == "" {
+			c.guild = c.user
+			c.dm = true
+		} else {
+			member, err := s.State.Member(c.guild, c.user)
+			if err != nil {
+				member, err = s.GuildMember(c.guild, c.user)
+			}
+			if err != nil {
+				fmt.Println("failed to get reacting member", err)
+				return
+			}
+
+			if member.User != nil && member.User.Bot && !b.Config.AllowBots {
+				return
+			}
+			c.roles = member.Roles
+
+			if channel, err := s.Channel(c.channel); err == nil {
+				c.category = channel.ParentID
+			}
+		}
+
+		lis, msg := b.getDDBList(c, list)
+		if msg != nil {
+			return
+		}
+
+		ok, lisErr := b.canEdit(c, lis)
+		if lisErr != nil {
+			lisErr.LogError()
+			return
+		}
+		if !ok {
+			return
+		}
+
+		// The list may have changed since the message was posted, so only act if the item is still where it was shown.
+		if index >= len(lis.List) || shownItem(message.Embeds[0], index) != lis.List[index].Label() {
+			if _, err := s.ChannelMessageEditEmbed(r.ChannelID, r.MessageID, reactEmbed(lis, mode)); err != nil {
+				fmt.Println("failed to update reaction list", err)
+			}
+			return
+		}
+
+		path := []int{index}
+		switch mode {
+		case doneMode:
+			item := lis.Item(path)
+			if item == nil {
+				return
+			}
+			lis.SetDone(path, !item.Done)
+			lis.MarkEditedPath(path, c.user, now.Unix())
+		case removeMode:
+			if lis.RemovePath(path) == "" {
+				return
+			}
+		case voteMode:
+			// Reacting again takes the vote back.
+			if !lis.Vote(path, c.user) && !lis.Unvote(path, c.user) {
+				return
+			}
+		default:
+			return
+		}
+
+		if err := b.putList(c.ctx, lis); err != nil {
+			err.LogError()
+			return
+		}
+
+		if _, err := s.ChannelMessageEditEmbed(r.ChannelID, r.MessageID, reactEmbed(lis, mode)); err != nil {
+			fmt.Println("failed to update reaction list", err)
+		}
+	}
+}
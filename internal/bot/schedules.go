@@ -0,0 +1,328 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// scheduleList sets, shows or removes a list's recurring reset.
+// The channel is used for summaries when "here" is given instead of a channel mention.
+func (b *bot) scheduleList(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	switch strings.ToLower(arg) {
+	case "":
+		return b.showSchedule(c.ctx, lis)
+	case "off":
+		if err := b.deleteSchedule(c.ctx, lis.Guild, lis.Name); err != nil {
+			err.LogError()
+			return storageErrMsg(err)
+		}
+
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s will no longer be reset", list),
+			Color:       green,
+		}
+	}
+
+	args := strings.Fields(arg)
+
+	var template *lists.Template
+	action := strings.ToLower(args[0])
+	if strings.HasPrefix(action, "from:") {
+		template, msg = b.getDDBTemplate(c, args[0][len("from:"):])
+		if msg != nil {
+			return msg
+		}
+		action = lists.TemplateAction
+	} else if action != lists.ClearAction && action != lists.UncheckAction {
+		return &discordgo.MessageEmbed{
+			Description: "A schedule can either clear the list, uncheck every item, or restore the list from a template with from:MyTemplate",
+			Color:       yellow,
+		}
+	}
+
+	var spec []string
+	var summaries string
+	for _, a := range args[1:] {
+		switch {
+		case strings.ToLower(a) == "here":
+			summaries = c.channel
+		case channelRegex.MatchString(a):
+			summaries = channelRegex.FindStringSubmatch(a)[1]
+			if msg := b.checkSummaryChannel(c, summaries); msg != nil {
+				return msg
+			}
+		default:
+			spec = append(spec, a)
+		}
+	}
+
+	schedule, err := lists.NewSchedule(lis.Guild, lis.Name, strings.Join(spec, " "), action, "", summaries, time.Now())
+	if err != nil {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("That schedule isn't quite right: %s", err.Message),
+			Color:       yellow,
+		}
+	}
+
+	if template != nil {
+		schedule.Template = template.Name
+		schedule.TemplateGuild = template.Guild
+	}
+
+	if err := b.putSchedule(c.ctx, schedule); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	return b.showSchedule(c.ctx, lis)
+}
+
+// checkSummaryChannel returns a message if summaries can't be sent to a channel,
+// as it is on another server or the caller can't send messages there.
+func (b *bot) checkSummaryChannel(c *caller, id string) *discordgo.MessageEmbed {
+	channel, err := b.Dgo.State.Channel(id)
+	if err != nil {
+		channel, err = b.Dgo.Channel(id)
+	}
+	if err != nil && !notFound(err) {
+		fmt.Println("failed to get summary channel", err)
+		return failMsg()
+	}
+
+	if err != nil || channel.GuildID != c.guild {
+		return &discordgo.MessageEmbed{
+			Description: "Summaries can only be sent to a channel on this server",
+			Color:       yellow,
+		}
+	}
+
+	perms, err := b.Dgo.UserChannelPermissions(c.user, id)
+	if err != nil {
+		fmt.Println("failed to get permissions", err)
+		return failMsg()
+	}
+
+	if perms&discordgo.PermissionSendMessages == 0 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("You can't send messages in <#%s>, so summaries can't go there", id),
+			Color:       yellow,
+		}
+	}
+
+	return nil
+}
+
+// showSchedule prints when a list will next be reset, and how.
+func (b *bot) showSchedule(ctx context.Context, lis *lists.ListtoList) *discordgo.MessageEmbed {
+	schedule, err := b.DDB.GetSchedule(ctx, lis.Guild, lis.Name)
+	if err != nil {
+		if err.Code == listtoErr.ScheduleNotFound {
+			return &discordgo.MessageEmbed{
+				Description: fmt.Sprintf("%s isn't scheduled to be reset", lis.Name),
+				Color:       yellow,
+			}
+		}
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	action := schedule.Action
+	if action == lists.TemplateAction {
+		action = fmt.Sprintf("restore from %s", schedule.Template)
+	}
+
+	summaries := "nowhere"
+	if schedule.Channel != "" {
+		summaries = fmt.Sprintf("<#%s>", schedule.Channel)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("Here's the schedule for %s", lis.Name),
+		Color:       green,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "When", Value: schedule.Spec, Inline: true},
+			{Name: "Action", Value: action, Inline: true},
+			{Name: "Summaries", Value: summaries, Inline: true},
+			{Name: "Next reset", Value: formatTime(schedule.NextRun)},
+		},
+	}
+}
+
+// scheduleTimes scans for when every schedule is next due.
+func (b *bot) scheduleTimes(ctx context.Context) (map[dueKey]int64, *listtoErr.ListtoError) {
+	schedules, err := b.DDB.GetAllSchedules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make(map[dueKey]int64, len(schedules))
+	for _, s := range schedules {
+		times[dueKey{guild: s.Guild, name: s.Name}] = s.NextRun
+	}
+
+	return times, nil
+}
+
+// checkSchedule reads a schedule that looks due, applying it if it still is.
+func (b *bot) checkSchedule(ctx context.Context, k dueKey, now time.Time) {
+	s, err := b.DDB.GetSchedule(ctx, k.guild, k.name)
+	if err != nil {
+		if err.Code == listtoErr.ScheduleNotFound {
+			b.schedules.remove(k.guild, k.name)
+			return
+		}
+		err.LogError()
+		return
+	}
+
+	if !s.Due(now) {
+		b.schedules.set(s.Guild, s.Name, s.NextRun)
+		return
+	}
+
+	b.applySchedule(ctx, s, now)
+}
+
+// putSchedule stores a schedule, and when it is next due.
+func (b *bot) putSchedule(ctx context.Context, s *lists.Schedule) *listtoErr.ListtoError {
+	if err := b.DDB.PutSchedule(ctx, s); err != nil {
+		return err
+	}
+
+	b.schedules.set(s.Guild, s.Name, s.NextRun)
+	return nil
+}
+
+// deleteSchedule deletes a list's schedule, so it is no longer due.
+func (b *bot) deleteSchedule(ctx context.Context, guild, name string) *listtoErr.ListtoError {
+	if err := b.DDB.DeleteSchedule(ctx, guild, name); err != nil {
+		return err
+	}
+
+	b.schedules.remove(guild, name)
+	return nil
+}
+
+// applySchedule resets a list as its schedule says, and posts a summary of how it went beforehand.
+func (b *bot) applySchedule(ctx context.Context, s *lists.Schedule, now time.Time) {
+	lis, err := b.DDB.GetList(ctx, s.Guild, s.Name)
+	if err != nil {
+		// The list has been deleted, so it no longer needs resetting.
+		if err.Code == listtoErr.ListNotFound {
+			err = b.deleteSchedule(ctx, s.Guild, s.Name)
+		}
+		if err != nil {
+			err.LogError()
+		}
+		return
+	}
+
+	summary := scheduleSummary(lis)
+
+	// Locked lists are left alone, but still move on to their next run.
+	if !lis.Locked {
+		var template *lists.Template
+		if s.Action == lists.TemplateAction {
+			template, err = b.DDB.GetTemplate(ctx, s.TemplateGuild, s.Template)
+			if err != nil {
+				err.LogError()
+			}
+		}
+
+		before := lis.List
+		s.Apply(lis, template, now)
+
+		// A template saved elsewhere, or before the limits were lowered, might not fit in the list.
+		if s.Action == lists.TemplateAction {
+			if msg := b.checkListSize(ctx, lis); msg != nil {
+				fmt.Println("not restoring scheduled list from template", msg.Description)
+				lis.List = before
+			}
+		}
+
+		if err := b.putList(ctx, lis); err != nil {
+			err.LogError()
+			return
+		}
+	}
+
+	next, err := s.Next(now)
+	if err != nil {
+		err.LogError()
+		return
+	}
+	s.NextRun = next.Unix()
+
+	if err := b.putSchedule(ctx, s); err != nil {
+		err.LogError()
+	}
+
+	if s.Channel != "" {
+		if _, err := b.Dgo.ChannelMessageSendEmbed(s.Channel, summary); err != nil {
+			fmt.Println("failed to send schedule summary", err)
+		}
+	}
+}
+
+// scheduleSummary describes how far through a list everybody got before it is reset.
+func scheduleSummary(lis *lists.ListtoList) *discordgo.MessageEmbed {
+	var done, todo []string
+	for _, l := range lis.List {
+		if l.Done {
+			done = append(done, l.Value)
+		} else {
+			todo = append(todo, l.Value)
+		}
+	}
+
+	doneCount, total := lis.CountDone()
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Done", Value: fmt.Sprintf("%d of %d", doneCount, total)},
+	}
+	if len(done) != 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Completed", Value: truncateLines(done)})
+	}
+	if len(todo) != 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Not completed", Value: truncateLines(todo)})
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("%s is being reset, here's how it went", lis.Name),
+		Color:       blue,
+		Fields:      fields,
+	}
+}
+
+// truncateLines joins lines together, stopping before they would be too long for an embed field.
+func truncateLines(lines []string) string {
+	var value string
+	for i, l := range lines {
+		if len(value)+len(l) > 1000 {
+			return fmt.Sprintf("%s\n...and %d more", value, len(lines)-i)
+		}
+		value = fmt.Sprintf("%s\n%s", value, l)
+	}
+
+	return value
+}
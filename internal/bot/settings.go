@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// setOption changes one of the settings on a list, or shows them all if no setting is given.
+func (b *bot) setOption(c *caller, list, arg string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	if arg == "" {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Here are the settings for %s", list),
+			Color:       blue,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "deck", Value: onOff(lis.Deck), Inline: true},
+				{Name: "autoremove", Value: onOff(lis.AutoRemove), Inline: true},
+				{Name: "notify", Value: onOff(lis.Notify), Inline: true},
+			},
+		}
+	}
+
+	args := strings.Split(strings.ToLower(arg), " ")
+	if len(args) != 2 {
+		return &discordgo.MessageEmbed{
+			Description: "You need to give me a setting and a value for it!",
+			Color:       yellow,
+		}
+	}
+
+	on, ok := parseOnOff(args[1])
+	if !ok {
+		return &discordgo.MessageEmbed{
+			Description: "That setting can only be turned \"on\" or \"off\"",
+			Color:       yellow,
+		}
+	}
+
+	switch args[0] {
+	case "deck":
+		lis.SetDeck(on)
+	case "autoremove":
+		lis.SetAutoRemove(on)
+	case "notify":
+		lis.Notify = on
+	default:
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I don't know of a setting called %s", args[0]),
+			Color:       yellow,
+		}
+	}
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have turned %s %s for %s", args[0], args[1], list),
+		Color:       green,
+	}
+}
+
+// parseOnOff reads a setting value, returning false for ok if it isn't recognised.
+func parseOnOff(value string) (on, ok bool) {
+	switch value {
+	case "on", "yes", "true":
+		return true, true
+	case "off", "no", "false":
+		return false, true
+	}
+
+	return false, false
+}
+
+// onOff prints a setting value.
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+
+	return "off"
+}
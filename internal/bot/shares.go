@@ -0,0 +1,278 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// shareList makes a code that lets another user join a personal list, wherever they are.
+// Lists are shared read only unless rw is given.
+func (b *bot) shareList(c *caller, list, friend, arg string) *discordgo.MessageEmbed {
+	if friend == "" {
+		return &discordgo.MessageEmbed{
+			Description: "You need to tell me who to share the list with!",
+			Color:       yellow,
+		}
+	}
+
+	lis, msg := b.getOwnedList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	var write bool
+	for _, a := range strings.Fields(arg) {
+		switch strings.ToLower(a) {
+		case "rw":
+			write = true
+		case "ro":
+			write = false
+		}
+	}
+
+	share := lists.NewShare(lis.Guild, list, friend, write, time.Now())
+	if err := b.DDB.PutShare(c.ctx, share); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't share %s", list),
+			Color:       red,
+		})
+	}
+
+	access := "read"
+	if write {
+		access = "read and change"
+	}
+
+	b.sendShareCode(friend, c.user, list, access, share.Code)
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("<@%s> can now %s %s by sending me %sjoin %s within the next day", friend, access, list, b.Config.Prefix, share.Code),
+		Color:       green,
+	}
+}
+
+// sendShareCode lets a user know they have been invited to a list, if they can be messaged.
+func (b *bot) sendShareCode(friend, owner, list, access, code string) {
+	channel, err := b.Dgo.UserChannelCreate(friend)
+	if err != nil {
+		fmt.Println("failed to create DM channel", err)
+		return
+	}
+
+	_, err = b.Dgo.ChannelMessageSendEmbed(channel.ID, &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("<@%s> has invited you to %s %s. Send me %sjoin %s to accept", owner, access, list, b.Config.Prefix, code),
+		Color:       blue,
+	})
+	if err != nil {
+		fmt.Println("failed to send share code", err)
+	}
+}
+
+// joinList redeems a share code, giving the user access to the shared list.
+func (b *bot) joinList(c *caller, code string) *discordgo.MessageEmbed {
+	invalid := &discordgo.MessageEmbed{
+		Description: "That share code isn't valid. Ask for a new one!",
+		Color:       yellow,
+	}
+
+	if code == "" {
+		return &discordgo.MessageEmbed{
+			Description: "You need to give me a share code!",
+			Color:       yellow,
+		}
+	}
+
+	share, err := b.DDB.GetShare(c.ctx, strings.ToUpper(code))
+	if err != nil {
+		if err.Code == listtoErr.ShareNotFound {
+			return invalid
+		}
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if share.Expired(time.Now()) {
+		if err := b.DDB.DeleteShare(c.ctx, share.Code); err != nil {
+			err.LogError()
+		}
+		return invalid
+	}
+
+	if share.User != c.user {
+		return &discordgo.MessageEmbed{
+			Description: "That share code is for somebody else",
+			Color:       yellow,
+		}
+	}
+
+	if _, err := b.DDB.GetList(c.ctx, c.user, share.Name); err == nil {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("You already have a list called %s", share.Name),
+			Color:       yellow,
+		}
+	}
+
+	settings, err := b.DDB.GetSettings(c.ctx, c.user)
+	if err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if owner := settings.JoinedOwner(share.Name); owner != "" && owner != share.Owner {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("You have already joined another list called %s", share.Name),
+			Color:       yellow,
+		}
+	}
+
+	lis, err := b.DDB.GetList(c.ctx, share.Owner, share.Name)
+	if err != nil {
+		if err.Code == listtoErr.ListNotFound {
+			return noList(share.Name)
+		}
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	lis.Share(c.user, share.Write)
+	settings.Join(share.Name, share.Owner)
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't add you to %s", share.Name),
+			Color:       red,
+		})
+	}
+
+	if err := b.DDB.PutSettings(c.ctx, settings); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if err := b.DDB.DeleteShare(c.ctx, share.Code); err != nil {
+		err.LogError()
+	}
+
+	desc := fmt.Sprintf("You have joined %s!", share.Name)
+	if !share.Write {
+		desc = fmt.Sprintf("You have joined %s, but you can only read it", share.Name)
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: desc,
+		Color:       green,
+	}
+}
+
+// unshareList takes away another user's access to a personal list.
+func (b *bot) unshareList(c *caller, list, friend string) *discordgo.MessageEmbed {
+	if friend == "" {
+		return &discordgo.MessageEmbed{
+			Description: "You need to tell me who to stop sharing the list with!",
+			Color:       yellow,
+		}
+	}
+
+	lis, msg := b.getOwnedList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if !lis.Unshare(friend) {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s isn't shared with <@%s>", list, friend),
+			Color:       yellow,
+		}
+	}
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't update the permissions for %s", list),
+			Color:       red,
+		})
+	}
+
+	settings, err := b.DDB.GetSettings(c.ctx, friend)
+	if err == nil && settings.JoinedOwner(list) == lis.Guild {
+		settings.Leave(list)
+		err = b.DDB.PutSettings(c.ctx, settings)
+	}
+	if err != nil {
+		err.LogError()
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have stopped sharing %s with <@%s>", list, friend),
+		Color:       green,
+	}
+}
+
+// getOwnedList gets a personal list, as long as it belongs to the caller.
+func (b *bot) getOwnedList(c *caller, list string) (*lists.ListtoList, *discordgo.MessageEmbed) {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return nil, msg
+	}
+
+	if lis.Type != lists.PersonalList {
+		return nil, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Only personal lists can be shared. Try %saddtoprivate for lists on a server", b.Config.Prefix),
+			Color:       yellow,
+		}
+	}
+
+	if lis.Guild != c.user {
+		return nil, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Only the owner of %s can share it", list),
+			Color:       yellow,
+		}
+	}
+
+	return lis, nil
+}
+
+// getJoinedList gets a personal list that has been shared with the caller.
+func (b *bot) getJoinedList(c *caller, list string) (*lists.ListtoList, *listtoErr.ListtoError) {
+	settings, err := b.DDB.GetSettings(c.ctx, c.user)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := settings.JoinedOwner(list)
+	if owner == "" {
+		return nil, listtoErr.ListNotFoundError(list)
+	}
+
+	return b.DDB.GetList(c.ctx, owner, list)
+}
+
+// joinedLists gets all the personal lists that have been shared with the caller.
+func (b *bot) joinedLists(c *caller) ([]*lists.ListtoList, *listtoErr.ListtoError) {
+	settings, err := b.DDB.GetSettings(c.ctx, c.user)
+	if err != nil {
+		return nil, err
+	}
+
+	var joined []*lists.ListtoList
+	for name, owner := range settings.Joined {
+		lis, err := b.DDB.GetList(c.ctx, owner, name)
+		if err != nil {
+			if err.Code == listtoErr.ListNotFound {
+				continue
+			}
+			return nil, err
+		}
+		joined = append(joined, lis)
+	}
+
+	return joined, nil
+}
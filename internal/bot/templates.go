@@ -0,0 +1,190 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+// template handles the template subcommands.
+func (b *bot) template(c *caller, command, arg string) *discordgo.MessageEmbed {
+	args := strings.Fields(arg)
+
+	switch command {
+	case "save", "s":
+		if len(args) < 2 {
+			return &discordgo.MessageEmbed{
+				Description: "You need to tell me which list to save, and what to call the template!",
+				Color:       yellow,
+			}
+		}
+		return b.saveTemplate(c, args[0], args[1], len(args) > 2 && strings.ToLower(args[2]) == "personal")
+	case "list", "l":
+		return b.listTemplates(c)
+	case "delete", "d":
+		if len(args) < 1 {
+			return &discordgo.MessageEmbed{
+				Description: "You need to tell me which template to delete!",
+				Color:       yellow,
+			}
+		}
+		return b.deleteTemplate(c, args[0])
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I can save, list or delete templates. Try %shelp lists for more", b.Config.Prefix),
+		Color:       yellow,
+	}
+}
+
+// saveTemplate saves a list's items and access settings as a template.
+// Personal templates are saved for the user, rather than the whole server.
+func (b *bot) saveTemplate(c *caller, list, name string, personal bool) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	owner := c.guild
+	if personal {
+		owner = c.user
+	}
+
+	existing, err := b.DDB.GetTemplate(c.ctx, owner, name)
+	if err == nil && existing.Creator != c.user {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Somebody else has already made a template called %s", name),
+			Color:       yellow,
+		}
+	}
+	if err != nil && err.Code != listtoErr.TemplateNotFound {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if err := b.DDB.PutTemplate(c.ctx, lists.NewTemplate(owner, name, c.user, lis)); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't save %s as a template", list),
+			Color:       red,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have saved %s as a template called %s. Use %screate MyList from:%s to use it", list, name, b.Config.Prefix, name),
+		Color:       green,
+	}
+}
+
+// listTemplates prints the templates on the server, and the user's personal templates.
+func (b *bot) listTemplates(c *caller) *discordgo.MessageEmbed {
+	templates, err := b.DDB.GetAllTemplates(c.ctx, c.guild, c.user)
+	if err != nil {
+		if err.Code == listtoErr.TemplateNotFound {
+			return &discordgo.MessageEmbed{
+				Description: "I couldn't find any templates for you",
+				Color:       yellow,
+			}
+		}
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	var server, personal string
+	for _, t := range templates {
+		if t.Guild == c.user {
+			personal = fmt.Sprintf("%s\n%s", personal, t.Name)
+		} else {
+			server = fmt.Sprintf("%s\n%s", server, t.Name)
+		}
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	if server != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Server templates", Value: server})
+	}
+	if personal != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Your templates", Value: personal})
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: "I found these templates!",
+		Color:       green,
+		Fields:      fields,
+	}
+}
+
+// deleteTemplate deletes a template, as long as the user created it.
+func (b *bot) deleteTemplate(c *caller, name string) *discordgo.MessageEmbed {
+	template, msg := b.getDDBTemplate(c, name)
+	if msg != nil {
+		return msg
+	}
+
+	if template.Creator != c.user {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("Only the creator of %s can delete it", name),
+			Color:       yellow,
+		}
+	}
+
+	if err := b.DDB.DeleteTemplate(c.ctx, template.Guild, name); err != nil {
+		err.LogError()
+		return storageErrMsgOr(err, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't delete %s", name),
+			Color:       red,
+		})
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have deleted the template %s", name),
+		Color:       green,
+	}
+}
+
+// createFromTemplate creates a new list holding a template's items.
+func (b *bot) createFromTemplate(c *caller, list, name, bind string) *discordgo.MessageEmbed {
+	template, msg := b.getDDBTemplate(c, name)
+	if msg != nil {
+		return msg
+	}
+
+	lis := template.NewList(c.guild, list, c.user, c.dm, time.Now().Unix())
+	lis.Channel = bind
+
+	return b.saveNewList(c.ctx, c.guild, list, lis)
+}
+
+// getDDBTemplate finds a template on the server, or one of the user's personal templates.
+func (b *bot) getDDBTemplate(c *caller, name string) (*lists.Template, *discordgo.MessageEmbed) {
+	template, err := b.DDB.GetTemplate(c.ctx, c.guild, name)
+	if err == nil {
+		return template, nil
+	}
+
+	if err.Code == listtoErr.TemplateNotFound && c.guild != c.user {
+		template, err = b.DDB.GetTemplate(c.ctx, c.user, name)
+		if err == nil {
+			return template, nil
+		}
+	}
+
+	if err.Code == listtoErr.TemplateNotFound {
+		return nil, &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I couldn't find a template called %s", name),
+			Color:       yellow,
+		}
+	}
+
+	err.LogError()
+	return nil, storageErrMsg(err)
+}
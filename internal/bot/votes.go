@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// voteInList adds or removes the user's vote for an item in the list.
+func (b *bot) voteInList(c *caller, list, arg string, vote bool) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	if msg = b.checkEdit(c, lis); msg != nil {
+		return msg
+	}
+
+	path := findPath(lis, arg)
+	item := lis.Item(path)
+	if item == nil {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s doesn't seem to contain %s", list, arg),
+			Color:       yellow,
+		}
+	}
+	value := item.Value
+
+	if vote && !lis.Vote(path, c.user) {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("You have already voted for %s", value),
+			Color:       yellow,
+		}
+	}
+
+	if !vote && !lis.Unvote(path, c.user) {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("You haven't voted for %s", value),
+			Color:       yellow,
+		}
+	}
+
+	if err := b.putList(c.ctx, lis); err != nil {
+		err.LogError()
+		return storageErrMsg(err)
+	}
+
+	if vote {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("I have counted your vote for %s in %s", value, list),
+			Color:       green,
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("I have removed your vote for %s in %s", value, list),
+		Color:       green,
+	}
+}
+
+// results shows the items in the list ordered by votes.
+func (b *bot) results(c *caller, list string) *discordgo.MessageEmbed {
+	lis, msg := b.getDDBList(c, list)
+	if msg != nil {
+		return msg
+	}
+
+	if msg = b.checkAccess(c, lis); msg != nil {
+		return msg
+	}
+
+	var lines []string
+	for i, item := range lis.Ranked() {
+		lines = append(lines, fmt.Sprintf("%d. %s - %d", i+1, item.Value, len(item.Votes)))
+	}
+
+	if len(lines) == 0 {
+		return &discordgo.MessageEmbed{
+			Description: fmt.Sprintf("%s is empty, so there's nothing to vote for", list),
+			Color:       yellow,
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Description: fmt.Sprintf("Here are the votes for %s", list),
+		Color:       green,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Leaderboard", Value: truncateLines(lines)},
+		},
+	}
+}
@@ -1,6 +1,11 @@
 package ddb
 
 import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 
@@ -14,15 +19,39 @@ const (
 
 type DDB struct {
 	DDB *dynamodb.DynamoDB
+
+	breaker *breaker
+}
+
+// New wraps a DynamoDB client, retrying throttled and failed requests up to retries times,
+// and failing requests straight away while storage keeps failing.
+func New(ddb *dynamodb.DynamoDB, retries int) *DDB {
+	d := &DDB{
+		DDB:     ddb,
+		breaker: new(breaker),
+	}
+
+	ddb.Retryer = newRetryer(retries)
+	d.breaker.handlers(&ddb.Handlers)
+
+	return d
 }
 
-func New(ddb *dynamodb.DynamoDB) *DDB {
-	return &DDB{
-		DDB: ddb,
+// convertError turns an AWS error into a ListtoError, marking requests that ran out of time.
+func convertError(err error) *listtoErr.ListtoError {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case request.CanceledErrorCode:
+			return listtoErr.TimeoutError(aerr.Message())
+		case circuitOpenCode:
+			return listtoErr.StorageUnavailableError()
+		}
 	}
+
+	return listtoErr.ConvertError(err)
 }
 
-func (d *DDB) GetList(guild, lis string) (list *lists.ListtoList, lisErr *listtoErr.ListtoError) {
+func (d *DDB) GetList(ctx context.Context, guild, lis string) (list *lists.ListtoList, lisErr *listtoErr.ListtoError) {
 	defer func() {
 		if lisErr != nil {
 			lisErr.SetCallingMethodIfNil("GetList")
@@ -34,9 +63,9 @@ func (d *DDB) GetList(guild, lis string) (list *lists.ListtoList, lisErr *listto
 		"name":  (&dynamodb.AttributeValue{}).SetS(lis),
 	})
 
-	output, err := d.DDB.GetItem(input)
+	output, err := d.DDB.GetItemWithContext(ctx, input)
 	if err != nil {
-		lisErr = listtoErr.ConvertError(err)
+		lisErr = convertError(err)
 		return
 	}
 
@@ -47,13 +76,13 @@ func (d *DDB) GetList(guild, lis string) (list *lists.ListtoList, lisErr *listto
 
 	list = new(lists.ListtoList)
 	if err := dynamodbattribute.UnmarshalMap(output.Item, &list); err != nil {
-		lisErr = listtoErr.ConvertError(err)
+		lisErr = convertError(err)
 	}
 
 	return
 }
 
-func (d *DDB) GetAllLists(guild, user string) (values []*lists.ListtoList, lisErr *listtoErr.ListtoError) {
+func (d *DDB) GetAllLists(ctx context.Context, guild, user string) (values []*lists.ListtoList, lisErr *listtoErr.ListtoError) {
 	defer func() {
 		if lisErr != nil {
 			lisErr.SetCallingMethodIfNil("GetAllLists")
@@ -63,9 +92,9 @@ func (d *DDB) GetAllLists(guild, user string) (values []*lists.ListtoList, lisEr
 	input := (&dynamodb.QueryInput{}).SetTableName(table).SetKeyConditionExpression("guild = :v1").
 		SetExpressionAttributeValues(map[string]*dynamodb.AttributeValue{":v1": (&dynamodb.AttributeValue{}).SetS(guild)})
 
-	output, err := d.DDB.Query(input)
+	output, err := d.DDB.QueryWithContext(ctx, input)
 	if err != nil {
-		lisErr = listtoErr.ConvertError(err)
+		lisErr = convertError(err)
 		return
 	}
 
@@ -75,9 +104,9 @@ func (d *DDB) GetAllLists(guild, user string) (values []*lists.ListtoList, lisEr
 		input2 := (&dynamodb.QueryInput{}).SetTableName(table).SetKeyConditionExpression("guild = :v1").
 			SetExpressionAttributeValues(map[string]*dynamodb.AttributeValue{":v1": (&dynamodb.AttributeValue{}).SetS(user)})
 
-		output2, err = d.DDB.Query(input2)
+		output2, err = d.DDB.QueryWithContext(ctx, input2)
 		if err != nil {
-			lisErr = listtoErr.ConvertError(err)
+			lisErr = convertError(err)
 			return
 		}
 	}
@@ -96,7 +125,7 @@ func (d *DDB) GetAllLists(guild, user string) (values []*lists.ListtoList, lisEr
 	for _, v := range output.Items {
 		lis := new(lists.ListtoList)
 		if err := dynamodbattribute.UnmarshalMap(v, &lis); err != nil {
-			lisErr = listtoErr.ConvertError(err)
+			lisErr = convertError(err)
 			return
 		}
 		values = append(values, lis)
@@ -106,7 +135,7 @@ func (d *DDB) GetAllLists(guild, user string) (values []*lists.ListtoList, lisEr
 		for _, v := range output2.Items {
 			lis := new(lists.ListtoList)
 			if err := dynamodbattribute.UnmarshalMap(v, &lis); err != nil {
-				lisErr = listtoErr.ConvertError(err)
+				lisErr = convertError(err)
 				return
 			}
 			values = append(values, lis)
@@ -116,7 +145,30 @@ func (d *DDB) GetAllLists(guild, user string) (values []*lists.ListtoList, lisEr
 	return
 }
 
-func (d *DDB) PutList(in interface{}) (lisErr *listtoErr.ListtoError) {
+// CountLists returns how many lists a guild has, without reading them.
+func (d *DDB) CountLists(ctx context.Context, guild string) (count int, lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("CountLists")
+		}
+	}()
+
+	input := (&dynamodb.QueryInput{}).SetTableName(table).SetKeyConditionExpression("guild = :v1").
+		SetExpressionAttributeValues(map[string]*dynamodb.AttributeValue{":v1": (&dynamodb.AttributeValue{}).SetS(guild)}).
+		SetSelect(dynamodb.SelectCount)
+
+	err := d.DDB.QueryPagesWithContext(ctx, input, func(output *dynamodb.QueryOutput, _ bool) bool {
+		count += int(aws.Int64Value(output.Count))
+		return true
+	})
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) PutList(ctx context.Context, in interface{}) (lisErr *listtoErr.ListtoError) {
 	defer func() {
 		if lisErr != nil {
 			lisErr.SetCallingMethodIfNil("PutList")
@@ -125,21 +177,21 @@ func (d *DDB) PutList(in interface{}) (lisErr *listtoErr.ListtoError) {
 
 	item, err := dynamodbattribute.MarshalMap(in)
 	if err != nil {
-		lisErr = listtoErr.ConvertError(err)
+		lisErr = convertError(err)
 		return
 	}
 
 	input := (&dynamodb.PutItemInput{}).SetTableName(table).SetItem(item)
 
-	_, err = d.DDB.PutItem(input)
+	_, err = d.DDB.PutItemWithContext(ctx, input)
 	if err != nil {
-		lisErr = listtoErr.ConvertError(err)
+		lisErr = convertError(err)
 	}
 
 	return
 }
 
-func (d *DDB) DeleteList(guild, lis, user string) (lisErr *listtoErr.ListtoError) {
+func (d *DDB) DeleteList(ctx context.Context, guild, lis, user string) (lisErr *listtoErr.ListtoError) {
 	defer func() {
 		if lisErr != nil {
 			lisErr.SetCallingMethodIfNil("DeleteList")
@@ -151,9 +203,9 @@ func (d *DDB) DeleteList(guild, lis, user string) (lisErr *listtoErr.ListtoError
 		"name":  (&dynamodb.AttributeValue{}).SetS(lis),
 	})
 
-	_, err := d.DDB.DeleteItem(input)
+	_, err := d.DDB.DeleteItemWithContext(ctx, input)
 	if err != nil {
-		lisErr = listtoErr.ConvertError(err)
+		lisErr = convertError(err)
 		return
 	}
 
@@ -164,10 +216,55 @@ func (d *DDB) DeleteList(guild, lis, user string) (lisErr *listtoErr.ListtoError
 			"name":  (&dynamodb.AttributeValue{}).SetS(lis),
 		})
 
-		_, err = d.DDB.DeleteItem(input)
+		_, err = d.DDB.DeleteItemWithContext(ctx, input)
 		if err != nil {
-			lisErr = listtoErr.ConvertError(err)
+			lisErr = convertError(err)
+		}
+	}
+
+	return
+}
+
+// MoveList stores a list under its new guild and deletes it from the old one in a single transaction,
+// failing with ListExists if there is already a list with the same name there.
+func (d *DDB) MoveList(ctx context.Context, lis *lists.ListtoList, from string) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("MoveList")
+		}
+	}()
+
+	item, err := dynamodbattribute.MarshalMap(lis)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	put := (&dynamodb.Put{}).SetTableName(table).SetItem(item).
+		SetConditionExpression("attribute_not_exists(#name)").
+		SetExpressionAttributeNames(map[string]*string{"#name": aws.String("name")})
+
+	del := (&dynamodb.Delete{}).SetTableName(table).SetKey(map[string]*dynamodb.AttributeValue{
+		"guild": (&dynamodb.AttributeValue{}).SetS(from),
+		"name":  (&dynamodb.AttributeValue{}).SetS(lis.Name),
+	})
+
+	input := (&dynamodb.TransactWriteItemsInput{}).SetTransactItems([]*dynamodb.TransactWriteItem{
+		(&dynamodb.TransactWriteItem{}).SetPut(put),
+		(&dynamodb.TransactWriteItem{}).SetDelete(del),
+	})
+
+	_, err = d.DDB.TransactWriteItemsWithContext(ctx, input)
+	if err != nil {
+		if cancelled, ok := err.(*dynamodb.TransactionCanceledException); ok {
+			for _, r := range cancelled.CancellationReasons {
+				if aws.StringValue(r.Code) == "ConditionalCheckFailed" {
+					lisErr = listtoErr.ListExistsError(lis.Name)
+					return
+				}
+			}
 		}
+		lisErr = convertError(err)
 	}
 
 	return
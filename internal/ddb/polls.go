@@ -0,0 +1,117 @@
+package ddb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+const (
+	pollTable = "listto_polls"
+)
+
+func (d *DDB) GetPoll(ctx context.Context, guild, lis string) (poll *lists.Poll, lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("GetPoll")
+		}
+	}()
+
+	input := (&dynamodb.GetItemInput{}).SetTableName(pollTable).SetKey(map[string]*dynamodb.AttributeValue{
+		"guild": (&dynamodb.AttributeValue{}).SetS(guild),
+		"name":  (&dynamodb.AttributeValue{}).SetS(lis),
+	})
+
+	output, err := d.DDB.GetItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	if len(output.Item) < 1 {
+		lisErr = listtoErr.PollNotFoundError(lis)
+		return
+	}
+
+	poll = new(lists.Poll)
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &poll); err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+// GetAllPolls scans for every poll. It is only used to find when polls end once the bot starts, so finished polls should be deleted.
+func (d *DDB) GetAllPolls(ctx context.Context) (polls []*lists.Poll, lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("GetAllPolls")
+		}
+	}()
+
+	input := (&dynamodb.ScanInput{}).SetTableName(pollTable)
+
+	err := d.DDB.ScanPagesWithContext(ctx, input, func(output *dynamodb.ScanOutput, _ bool) bool {
+		for _, v := range output.Items {
+			poll := new(lists.Poll)
+			if err := dynamodbattribute.UnmarshalMap(v, &poll); err != nil {
+				lisErr = convertError(err)
+				return false
+			}
+			polls = append(polls, poll)
+		}
+		return true
+	})
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) PutPoll(ctx context.Context, poll *lists.Poll) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("PutPoll")
+		}
+	}()
+
+	item, err := dynamodbattribute.MarshalMap(poll)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	input := (&dynamodb.PutItemInput{}).SetTableName(pollTable).SetItem(item)
+
+	_, err = d.DDB.PutItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) DeletePoll(ctx context.Context, guild, lis string) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("DeletePoll")
+		}
+	}()
+
+	input := (&dynamodb.DeleteItemInput{}).SetTableName(pollTable).SetKey(map[string]*dynamodb.AttributeValue{
+		"guild": (&dynamodb.AttributeValue{}).SetS(guild),
+		"name":  (&dynamodb.AttributeValue{}).SetS(lis),
+	})
+
+	_, err := d.DDB.DeleteItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
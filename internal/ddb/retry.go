@@ -0,0 +1,151 @@
+package ddb
+
+import (
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	minRetryDelay = 50 * time.Millisecond
+	maxRetryDelay = 2 * time.Second
+
+	// After breakerThreshold failures in a row the breaker opens, and calls fail straight away
+	// until breakerCooldown has passed and a trial call succeeds.
+	breakerThreshold = 5
+	breakerCooldown  = 30 * time.Second
+
+	circuitOpenCode = "CircuitOpen"
+)
+
+// retryer retries requests that were throttled or failed on the server, backing off exponentially with full jitter.
+// Other errors, such as a failed condition, won't go away by trying again so are returned straight away.
+type retryer struct {
+	retries int
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// newRetryer creates a retryer that tries a request up to retries more times.
+func newRetryer(retries int) *retryer {
+	return &retryer{
+		retries: retries,
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// MaxRetries returns how many times a request can be retried.
+func (r *retryer) MaxRetries() int {
+	return r.retries
+}
+
+// ShouldRetry returns if a request was throttled, or failed with a server error.
+func (r *retryer) ShouldRetry(req *request.Request) bool {
+	return req.IsErrorThrottle() || serverError(req)
+}
+
+// RetryRules returns a random delay up to the exponential backoff for the attempt.
+func (r *retryer) RetryRules(req *request.Request) time.Duration {
+	backoff := minRetryDelay << uint(req.RetryCount)
+	if backoff <= 0 || backoff > maxRetryDelay {
+		backoff = maxRetryDelay
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return time.Duration(r.rng.Int63n(int64(backoff))) + time.Millisecond
+}
+
+// serverError returns if a request failed because of a problem on the server.
+func serverError(req *request.Request) bool {
+	return req.HTTPResponse != nil && req.HTTPResponse.StatusCode >= http.StatusInternalServerError
+}
+
+// breaker stops calls to storage while it keeps failing, so commands fail quickly rather than each waiting to time out.
+type breaker struct {
+	mu       sync.Mutex
+	failures int
+	open     bool
+	openedAt time.Time
+	trial    bool
+}
+
+// allow returns if a call can go ahead. Once the cooldown has passed, one trial call is let through to see if storage has recovered.
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return true
+	}
+
+	if now.Sub(b.openedAt) >= breakerCooldown && !b.trial {
+		b.trial = true
+		return true
+	}
+
+	return false
+}
+
+// record updates the breaker with whether a finished call failed.
+func (b *breaker) record(failed bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.failures = 0
+		b.open = false
+		b.trial = false
+		return
+	}
+
+	b.failures++
+	if b.trial || b.failures >= breakerThreshold {
+		b.open = true
+		b.openedAt = now
+		b.trial = false
+	}
+}
+
+// handlers adds the breaker to a client's requests, failing them before they are sent while it is open.
+func (b *breaker) handlers(h *request.Handlers) {
+	h.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "listto.breaker.allow",
+		Fn: func(req *request.Request) {
+			if !b.allow(time.Now()) {
+				req.Error = awserr.New(circuitOpenCode, "storage is temporarily unavailable", nil)
+			}
+		},
+	})
+	h.Complete.PushBackNamed(request.NamedHandler{
+		Name: "listto.breaker.record",
+		Fn: func(req *request.Request) {
+			if aerr, ok := req.Error.(awserr.Error); ok && aerr.Code() == circuitOpenCode {
+				return
+			}
+			b.record(storageFailure(req), time.Now())
+		},
+	})
+}
+
+// storageFailure returns if a finished request shows storage isn't answering properly.
+// Throttling, server errors, timeouts and requests that couldn't reach storage at all count,
+// but anything else, such as a failed condition, means storage is still answering.
+func storageFailure(req *request.Request) bool {
+	if req.Error == nil {
+		return false
+	}
+
+	if req.IsErrorThrottle() || serverError(req) {
+		return true
+	}
+
+	aerr, ok := req.Error.(awserr.Error)
+	return ok && (aerr.Code() == request.CanceledErrorCode || aerr.Code() == request.ErrCodeRequestError)
+}
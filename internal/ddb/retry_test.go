@@ -0,0 +1,107 @@
+package ddb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	start := time.Unix(0, 0)
+
+	type step struct {
+		at    time.Duration
+		fail  bool
+		check bool
+		want  bool
+	}
+
+	// Steps either record a call that failed or not, or check that allow returns want.
+	failures := func(at time.Duration, n int) []step {
+		steps := make([]step, n)
+		for i := range steps {
+			steps[i] = step{at: at, fail: true}
+		}
+		return steps
+	}
+	join := func(parts ...[]step) []step {
+		var steps []step
+		for _, p := range parts {
+			steps = append(steps, p...)
+		}
+		return steps
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name:  "closed to start with",
+			steps: []step{{check: true, want: true}},
+		},
+		{
+			name:  "stays closed below the threshold",
+			steps: join(failures(0, breakerThreshold-1), []step{{check: true, want: true}}),
+		},
+		{
+			name: "a success resets the failures",
+			steps: join(
+				failures(0, breakerThreshold-1),
+				[]step{{}},
+				failures(0, breakerThreshold-1),
+				[]step{{check: true, want: true}},
+			),
+		},
+		{
+			name:  "opens at the threshold",
+			steps: join(failures(0, breakerThreshold), []step{{check: true, want: false}}),
+		},
+		{
+			name: "stays open until the cooldown has passed",
+			steps: join(failures(0, breakerThreshold), []step{
+				{at: breakerCooldown - time.Second, check: true, want: false},
+			}),
+		},
+		{
+			name: "lets one trial call through after the cooldown",
+			steps: join(failures(0, breakerThreshold), []step{
+				{at: breakerCooldown, check: true, want: true},
+				{at: breakerCooldown, check: true, want: false},
+			}),
+		},
+		{
+			name: "closes when the trial call succeeds",
+			steps: join(failures(0, breakerThreshold), []step{
+				{at: breakerCooldown, check: true, want: true},
+				{at: breakerCooldown},
+				{at: breakerCooldown, check: true, want: true},
+				{at: breakerCooldown, check: true, want: true},
+			}),
+		},
+		{
+			name: "opens again for another cooldown when the trial call fails",
+			steps: join(failures(0, breakerThreshold), []step{
+				{at: breakerCooldown, check: true, want: true},
+				{at: breakerCooldown, fail: true},
+				{at: 2*breakerCooldown - time.Second, check: true, want: false},
+				{at: 2 * breakerCooldown, check: true, want: true},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := new(breaker)
+			for i, s := range tt.steps {
+				now := start.Add(s.at)
+				if !s.check {
+					b.record(s.fail, now)
+					continue
+				}
+				if got := b.allow(now); got != s.want {
+					t.Fatalf("step %d: allow() = %t, want %t", i, got, s.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package ddb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+const (
+	scheduleTable = "listto_schedules"
+)
+
+func (d *DDB) GetSchedule(ctx context.Context, guild, lis string) (schedule *lists.Schedule, lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("GetSchedule")
+		}
+	}()
+
+	input := (&dynamodb.GetItemInput{}).SetTableName(scheduleTable).SetKey(map[string]*dynamodb.AttributeValue{
+		"guild": (&dynamodb.AttributeValue{}).SetS(guild),
+		"name":  (&dynamodb.AttributeValue{}).SetS(lis),
+	})
+
+	output, err := d.DDB.GetItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	if len(output.Item) < 1 {
+		lisErr = listtoErr.ScheduleNotFoundError(lis)
+		return
+	}
+
+	schedule = new(lists.Schedule)
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &schedule); err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+// GetAllSchedules scans for every schedule. It is only used to find when schedules are due once the bot starts.
+func (d *DDB) GetAllSchedules(ctx context.Context) (schedules []*lists.Schedule, lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("GetAllSchedules")
+		}
+	}()
+
+	input := (&dynamodb.ScanInput{}).SetTableName(scheduleTable)
+
+	err := d.DDB.ScanPagesWithContext(ctx, input, func(output *dynamodb.ScanOutput, _ bool) bool {
+		for _, v := range output.Items {
+			schedule := new(lists.Schedule)
+			if err := dynamodbattribute.UnmarshalMap(v, &schedule); err != nil {
+				lisErr = convertError(err)
+				return false
+			}
+			schedules = append(schedules, schedule)
+		}
+		return true
+	})
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) PutSchedule(ctx context.Context, schedule *lists.Schedule) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("PutSchedule")
+		}
+	}()
+
+	item, err := dynamodbattribute.MarshalMap(schedule)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	input := (&dynamodb.PutItemInput{}).SetTableName(scheduleTable).SetItem(item)
+
+	_, err = d.DDB.PutItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) DeleteSchedule(ctx context.Context, guild, lis string) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("DeleteSchedule")
+		}
+	}()
+
+	input := (&dynamodb.DeleteItemInput{}).SetTableName(scheduleTable).SetKey(map[string]*dynamodb.AttributeValue{
+		"guild": (&dynamodb.AttributeValue{}).SetS(guild),
+		"name":  (&dynamodb.AttributeValue{}).SetS(lis),
+	})
+
+	_, err := d.DDB.DeleteItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
@@ -0,0 +1,68 @@
+package ddb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+const (
+	settingsTable = "listto_settings"
+)
+
+// GetSettings returns the settings for a guild, or the defaults if none have been saved.
+func (d *DDB) GetSettings(ctx context.Context, guild string) (settings *lists.Settings, lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("GetSettings")
+		}
+	}()
+
+	input := (&dynamodb.GetItemInput{}).SetTableName(settingsTable).SetKey(map[string]*dynamodb.AttributeValue{
+		"guild": (&dynamodb.AttributeValue{}).SetS(guild),
+	})
+
+	output, err := d.DDB.GetItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	settings = lists.NewSettings(guild)
+	if len(output.Item) < 1 {
+		return
+	}
+
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &settings); err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) PutSettings(ctx context.Context, settings *lists.Settings) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("PutSettings")
+		}
+	}()
+
+	item, err := dynamodbattribute.MarshalMap(settings)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	input := (&dynamodb.PutItemInput{}).SetTableName(settingsTable).SetItem(item)
+
+	_, err = d.DDB.PutItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
@@ -0,0 +1,87 @@
+package ddb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+const (
+	shareTable = "listto_shares"
+)
+
+func (d *DDB) GetShare(ctx context.Context, code string) (share *lists.Share, lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("GetShare")
+		}
+	}()
+
+	input := (&dynamodb.GetItemInput{}).SetTableName(shareTable).SetKey(map[string]*dynamodb.AttributeValue{
+		"code": (&dynamodb.AttributeValue{}).SetS(code),
+	})
+
+	output, err := d.DDB.GetItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	if len(output.Item) < 1 {
+		lisErr = listtoErr.ShareNotFoundError(code)
+		return
+	}
+
+	share = new(lists.Share)
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &share); err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) PutShare(ctx context.Context, share *lists.Share) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("PutShare")
+		}
+	}()
+
+	item, err := dynamodbattribute.MarshalMap(share)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	input := (&dynamodb.PutItemInput{}).SetTableName(shareTable).SetItem(item)
+
+	_, err = d.DDB.PutItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) DeleteShare(ctx context.Context, code string) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("DeleteShare")
+		}
+	}()
+
+	input := (&dynamodb.DeleteItemInput{}).SetTableName(shareTable).SetKey(map[string]*dynamodb.AttributeValue{
+		"code": (&dynamodb.AttributeValue{}).SetS(code),
+	})
+
+	_, err := d.DDB.DeleteItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
@@ -0,0 +1,128 @@
+package ddb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"github.com/DarkieSouls/listto/internal/lists"
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+const (
+	templateTable = "listto_templates"
+)
+
+func (d *DDB) GetTemplate(ctx context.Context, guild, name string) (template *lists.Template, lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("GetTemplate")
+		}
+	}()
+
+	input := (&dynamodb.GetItemInput{}).SetTableName(templateTable).SetKey(map[string]*dynamodb.AttributeValue{
+		"guild": (&dynamodb.AttributeValue{}).SetS(guild),
+		"name":  (&dynamodb.AttributeValue{}).SetS(name),
+	})
+
+	output, err := d.DDB.GetItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	if len(output.Item) < 1 {
+		lisErr = listtoErr.TemplateNotFoundError(name)
+		return
+	}
+
+	template = new(lists.Template)
+	if err := dynamodbattribute.UnmarshalMap(output.Item, &template); err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) GetAllTemplates(ctx context.Context, guild, user string) (templates []*lists.Template, lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("GetAllTemplates")
+		}
+	}()
+
+	partitions := []string{guild}
+	if guild != user {
+		partitions = append(partitions, user)
+	}
+
+	for _, p := range partitions {
+		input := (&dynamodb.QueryInput{}).SetTableName(templateTable).SetKeyConditionExpression("guild = :v1").
+			SetExpressionAttributeValues(map[string]*dynamodb.AttributeValue{":v1": (&dynamodb.AttributeValue{}).SetS(p)})
+
+		output, err := d.DDB.QueryWithContext(ctx, input)
+		if err != nil {
+			lisErr = convertError(err)
+			return
+		}
+
+		for _, v := range output.Items {
+			template := new(lists.Template)
+			if err := dynamodbattribute.UnmarshalMap(v, &template); err != nil {
+				lisErr = convertError(err)
+				return
+			}
+			templates = append(templates, template)
+		}
+	}
+
+	if len(templates) < 1 {
+		lisErr = listtoErr.TemplatesNotFoundError()
+	}
+
+	return
+}
+
+func (d *DDB) PutTemplate(ctx context.Context, template *lists.Template) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("PutTemplate")
+		}
+	}()
+
+	item, err := dynamodbattribute.MarshalMap(template)
+	if err != nil {
+		lisErr = convertError(err)
+		return
+	}
+
+	input := (&dynamodb.PutItemInput{}).SetTableName(templateTable).SetItem(item)
+
+	_, err = d.DDB.PutItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
+
+func (d *DDB) DeleteTemplate(ctx context.Context, guild, name string) (lisErr *listtoErr.ListtoError) {
+	defer func() {
+		if lisErr != nil {
+			lisErr.SetCallingMethodIfNil("DeleteTemplate")
+		}
+	}()
+
+	input := (&dynamodb.DeleteItemInput{}).SetTableName(templateTable).SetKey(map[string]*dynamodb.AttributeValue{
+		"guild": (&dynamodb.AttributeValue{}).SetS(guild),
+		"name":  (&dynamodb.AttributeValue{}).SetS(name),
+	})
+
+	_, err := d.DDB.DeleteItemWithContext(ctx, input)
+	if err != nil {
+		lisErr = convertError(err)
+	}
+
+	return
+}
@@ -0,0 +1,42 @@
+package lists
+
+const (
+	DefaultMaxLists      = 100
+	DefaultMaxItems      = 250
+	DefaultMaxItemLength = 200
+
+	// MaxItemLength is the most an item length can be set to, so an item always fits in a message.
+	MaxItemLength = 1000
+)
+
+// Limits caps how much a guild can store, so one user can't fill the table or make lists too big to show.
+// For personal lists the Limits are the user's. Any limit left at zero uses the default.
+type Limits struct {
+	Lists      int `json:"lists,omitempty"`
+	Items      int `json:"items,omitempty"`
+	ItemLength int `json:"itemLength,omitempty"`
+}
+
+// MaxLists returns how many lists can be created.
+func (l Limits) MaxLists() int {
+	return orDefault(l.Lists, DefaultMaxLists)
+}
+
+// MaxItems returns how many items, including nested items, a list can hold.
+func (l Limits) MaxItems() int {
+	return orDefault(l.Items, DefaultMaxItems)
+}
+
+// MaxItemLength returns how many characters an item can have.
+func (l Limits) MaxItemLength() int {
+	return orDefault(l.ItemLength, DefaultMaxItemLength)
+}
+
+// orDefault returns the value, or the default if it hasn't been set.
+func orDefault(value, def int) int {
+	if value <= 0 {
+		return def
+	}
+
+	return value
+}
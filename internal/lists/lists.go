@@ -1,10 +1,14 @@
 package lists
 
 import (
+	"fmt"
 	"math/rand"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -12,24 +16,142 @@ const (
 	PublicList            = "Public"
 	PrivateList           = "Private"
 	PersonalList          = "Personal"
+	// RestrictedList can be read by everybody, but only changed by those given access.
+	RestrictedList = "Restricted"
 )
 
+// rng is shared between all lists, so picks made close together still differ.
+var rng = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
 // ListType denotes the type of ListtoList
 type ListType string
 
 // ListtoList defines the list object that holds all needed data for each list
 type ListtoList struct {
-	Guild  string     `json:"guild"`
-	Name   string     `json:"name"`
-	Type   ListType   `json:"type"`
-	Access []string   `json:"access"`
-	List   []ListItem `json:"list"`
+	Guild      string     `json:"guild"`
+	Name       string     `json:"name"`
+	Type       ListType   `json:"type"`
+	Access     []string   `json:"access"`
+	List       []ListItem `json:"list"`
+	Deck       bool       `json:"deck,omitempty"`
+	Drawn      []string   `json:"drawn,omitempty"`
+	AutoRemove bool       `json:"autoRemove,omitempty"`
+	Notify     bool       `json:"notify,omitempty"`
+	Pin        *Pin       `json:"pin,omitempty"`
+	Channel    string     `json:"channel,omitempty"`
+	Viewers    []string   `json:"viewers,omitempty"`
+	Locked     bool       `json:"locked,omitempty"`
+}
+
+// Pin is a message showing a ListtoList that is kept up to date as the ListtoList changes.
+type Pin struct {
+	Channel string `json:"channel"`
+	Message string `json:"message"`
 }
 
 // ListItem represents a single value in a list.
 type ListItem struct {
-	Value     string `json:"value"`
-	TimeAdded int64  `json:"timeAdded"`
+	Value      string     `json:"value"`
+	TimeAdded  int64      `json:"timeAdded"`
+	Weight     int        `json:"weight,omitempty"`
+	Quantity   *int       `json:"quantity,omitempty"`
+	Assignee   string     `json:"assignee,omitempty"`
+	Tags       []string   `json:"tags,omitempty"`
+	Note       string     `json:"note,omitempty"`
+	AddedBy    string     `json:"addedBy,omitempty"`
+	EditedBy   string     `json:"editedBy,omitempty"`
+	TimeEdited int64      `json:"timeEdited,omitempty"`
+	Children   []ListItem `json:"children,omitempty"`
+	Done       bool       `json:"done,omitempty"`
+	Votes      []string   `json:"votes,omitempty"`
+}
+
+// Count returns how many of the ListItem there are. Items without a quantity count as one.
+func (i ListItem) Count() int {
+	if i.Quantity == nil {
+		return 1
+	}
+
+	return *i.Quantity
+}
+
+// Label returns the ListItem as it should be shown in a list.
+func (i ListItem) Label() string {
+	label := i.Value
+	if i.Quantity != nil && *i.Quantity != 1 {
+		label = fmt.Sprintf("%s x%d", label, *i.Quantity)
+	}
+
+	for _, t := range i.Tags {
+		label = fmt.Sprintf("%s #%s", label, t)
+	}
+
+	if i.Assignee != "" {
+		label = fmt.Sprintf("%s - <@%s>", label, i.Assignee)
+	}
+
+	if len(i.Votes) == 1 {
+		label = fmt.Sprintf("%s (1 vote)", label)
+	} else if len(i.Votes) > 1 {
+		label = fmt.Sprintf("%s (%d votes)", label, len(i.Votes))
+	}
+
+	if i.Done {
+		label = fmt.Sprintf("~~%s~~ ✅", label)
+	}
+
+	return label
+}
+
+// HasTags returns if the ListItem has all of the given tags.
+func (i ListItem) HasTags(tags []string) bool {
+	for _, t := range tags {
+		if !contains(i.Tags, t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseTags splits hashtag style tags out of a value, returning the value without them.
+// Tags are lowercased so that filtering by them ignores case.
+func ParseTags(value string) (string, []string) {
+	var words, tags []string
+	for _, w := range strings.Fields(value) {
+		if IsTag(w) {
+			tag := strings.ToLower(strings.TrimPrefix(w, "#"))
+			if !contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+			continue
+		}
+		words = append(words, w)
+	}
+
+	return strings.Join(words, " "), tags
+}
+
+// IsTag returns if a word is a hashtag style tag. Tags start with a letter, so references such as #123 are left alone.
+func IsTag(word string) bool {
+	if !strings.HasPrefix(word, "#") {
+		return false
+	}
+
+	first, _ := utf8.DecodeRuneInString(strings.TrimPrefix(word, "#"))
+	return unicode.IsLetter(first)
+}
+
+// weight of the ListItem when picking randomly. Unweighted items count once.
+func (i ListItem) weight() int {
+	if i.Weight < 1 {
+		return 1
+	}
+
+	return i.Weight
 }
 
 // NewList returns a new ListtoList object.
@@ -42,15 +164,163 @@ func NewList(guild, name string, lType ListType) *ListtoList {
 }
 
 // AddItem to a ListtoList.
-func (l *ListtoList) AddItem(item string, timeAdded int64) {
-	l.List = append(l.List, ListItem{Value: item, TimeAdded: timeAdded})
+func (l *ListtoList) AddItem(item, user string, timeAdded int64) {
+	l.List = append(l.List, ListItem{Value: item, TimeAdded: timeAdded, AddedBy: user})
+}
+
+// AddQuantity of an Item to a ListtoList, adding to the existing Item if there is one.
+// Returns the new quantity of the Item.
+func (l *ListtoList) AddQuantity(item, user string, quantity int, timeAdded int64) int {
+	if i := l.FindItem(item); i != -1 {
+		l.MarkEdited(i, user, timeAdded)
+		count, _ := l.ChangeQuantity(i, quantity)
+		return count
+	}
+
+	l.AddItem(item, user, timeAdded)
+	if quantity != 1 {
+		l.List[len(l.List)-1].Quantity = &quantity
+	}
+
+	return quantity
+}
+
+// ChangeQuantity of an Item in a ListtoList by the given amount, never going below zero.
+// If the ListtoList removes empty Items, an Item reaching zero is removed.
+func (l *ListtoList) ChangeQuantity(index, by int) (count int, removed bool) {
+	if index < 0 || index >= len(l.List) {
+		return 0, false
+	}
+
+	count = l.List[index].Count() + by
+	if count < 0 {
+		count = 0
+	}
+
+	if count == 0 && l.AutoRemove {
+		l.List = append(l.List[:index], l.List[index+1:]...)
+		return 0, true
+	}
+
+	l.List[index].Quantity = &count
+
+	return count, false
+}
+
+// SetNote on an Item in the ListtoList. An empty note removes it.
+func (l *ListtoList) SetNote(index int, note string) string {
+	if index < 0 || index >= len(l.List) {
+		return ""
+	}
+
+	l.List[index].Note = note
+
+	return l.List[index].Value
+}
+
+// Assign an Item in the ListtoList to a user. An empty user unassigns the Item.
+func (l *ListtoList) Assign(index int, user string) string {
+	if index < 0 || index >= len(l.List) {
+		return ""
+	}
+
+	l.List[index].Assignee = user
+
+	return l.List[index].Value
+}
+
+// AssignedTo returns all Items in the ListtoList assigned to a user.
+func (l *ListtoList) AssignedTo(user string) []ListItem {
+	var items []ListItem
+	for _, v := range l.List {
+		if v.Assignee == user {
+			items = append(items, v)
+		}
+	}
+
+	return items
+}
+
+// AddTags to an Item in the ListtoList.
+func (l *ListtoList) AddTags(index int, tags []string) string {
+	if index < 0 || index >= len(l.List) {
+		return ""
+	}
+
+	for _, t := range tags {
+		if !contains(l.List[index].Tags, t) {
+			l.List[index].Tags = append(l.List[index].Tags, t)
+		}
+	}
+
+	return l.List[index].Value
+}
+
+// RemoveTags from an Item in the ListtoList.
+func (l *ListtoList) RemoveTags(index int, tags []string) string {
+	if index < 0 || index >= len(l.List) {
+		return ""
+	}
+
+	var kept []string
+	for _, t := range l.List[index].Tags {
+		if !contains(tags, t) {
+			kept = append(kept, t)
+		}
+	}
+	l.List[index].Tags = kept
+
+	return l.List[index].Value
+}
+
+// Filter returns all Items in the ListtoList with all of the given tags,
+// and added by the given user if one is given.
+func (l *ListtoList) Filter(tags []string, addedBy string) []ListItem {
+	var items []ListItem
+	for _, v := range l.List {
+		if v.HasTags(tags) && (addedBy == "" || v.AddedBy == addedBy) {
+			items = append(items, v)
+		}
+	}
+
+	return items
+}
+
+// TagCounts returns how many Items in the ListtoList have each tag.
+func (l *ListtoList) TagCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, v := range l.List {
+		for _, t := range v.Tags {
+			counts[t]++
+		}
+	}
+
+	return counts
+}
+
+// SetAutoRemove sets if Items are removed from the ListtoList when their quantity reaches zero.
+// Turning it on removes any Items already at zero.
+func (l *ListtoList) SetAutoRemove(autoRemove bool) {
+	l.AutoRemove = autoRemove
+	if !autoRemove {
+		return
+	}
+
+	items := make([]ListItem, 0, len(l.List))
+	for _, v := range l.List {
+		if v.Count() > 0 {
+			items = append(items, v)
+		}
+	}
+	l.List = items
 }
 
 // EditItem in a ListtoList.
-func (l *ListtoList) EditItem(old, update string) string {
+func (l *ListtoList) EditItem(old, update, user string, timeEdited int64) string {
 	for i, v := range l.List {
 		if v.Value == old {
 			l.List[i].Value = update
+			l.MarkEdited(i, user, timeEdited)
 			return "success"
 		}
 	}
@@ -58,15 +328,14 @@ func (l *ListtoList) EditItem(old, update string) string {
 	return ""
 }
 
-func (l *ListtoList) EditIndex(index int, value string) string {
-	if index > len(l.List) {
-		return ""
+// MarkEdited records who last edited an Item in a ListtoList, and when.
+func (l *ListtoList) MarkEdited(index int, user string, timeEdited int64) {
+	if index < 0 || index >= len(l.List) {
+		return
 	}
 
-	name := l.List[index].Value
-	l.List[index].Value = value
-
-	return name
+	l.List[index].EditedBy = user
+	l.List[index].TimeEdited = timeEdited
 }
 
 // RemoveItem from a ListtoList.
@@ -81,16 +350,44 @@ func (l *ListtoList) RemoveItem(item string) string {
 	return ""
 }
 
-// RemoveIndex item from ListtoList.
-func (l *ListtoList) RemoveIndex(index int) string {
-	if index > len(l.List) {
+// FindItem returns the index of an item in a ListtoList, or -1 if it isn't there.
+func (l *ListtoList) FindItem(item string) int {
+	for i, v := range l.List {
+		if v.Value == item {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// MoveIndex moves an item in a ListtoList to a new position.
+// Positions past the end of the list move the item to the bottom.
+func (l *ListtoList) MoveIndex(from, to int) string {
+	if from < 0 || from >= len(l.List) || to < 0 {
 		return ""
 	}
 
-	name := l.List[index].Value
-	l.List = append(l.List[:index], l.List[index+1:]...)
+	if to >= len(l.List) {
+		to = len(l.List) - 1
+	}
+
+	item := l.List[from]
+	l.List = append(l.List[:from], l.List[from+1:]...)
+	l.List = append(l.List[:to], append([]ListItem{item}, l.List[to:]...)...)
 
-	return name
+	return item.Value
+}
+
+// SwapIndex swaps the positions of two items in a ListtoList.
+func (l *ListtoList) SwapIndex(i, j int) bool {
+	if i < 0 || j < 0 || i >= len(l.List) || j >= len(l.List) {
+		return false
+	}
+
+	l.List[i], l.List[j] = l.List[j], l.List[i]
+
+	return true
 }
 
 // Clear a ListtoList of all Items.
@@ -98,20 +395,105 @@ func (l *ListtoList) Clear() {
 	l.List = make([]ListItem, 0)
 }
 
-// SelectItem from the ListtoList.
-func (l *ListtoList) SelectItem(item int) string {
-	if len(l.List) > item {
-		return l.List[item].Value
+// SelectRandom Item from a ListtoList.
+func (l *ListtoList) SelectRandom() string {
+	picked := l.SelectRandoms(1)
+	if len(picked) == 0 {
+		return ""
 	}
 
-	return ""
+	return picked[0]
 }
 
-// SelectRandom Item from a ListtoList.
-func (l *ListtoList) SelectRandom() string {
-	r := rand.New(rand.NewSource(time.Now().Unix()))
-	i := r.Intn(len(l.List))
-	return l.List[i].Value
+// SelectRandoms picks up to n distinct Items from a ListtoList, taking their weights into account.
+// If the ListtoList is in deck mode, Items already drawn are skipped until every Item has been drawn.
+func (l *ListtoList) SelectRandoms(n int) []string {
+	var picked []string
+
+	pool := l.pool(picked)
+	for len(picked) < n {
+		if len(pool) == 0 {
+			if !l.Deck || len(l.Drawn) == 0 {
+				break
+			}
+
+			// The deck has run out, so shuffle everything back in.
+			l.Drawn = nil
+			pool = l.pool(picked)
+			if len(pool) == 0 {
+				break
+			}
+		}
+
+		value := l.List[l.pickWeighted(pool)].Value
+		picked = append(picked, value)
+		if l.Deck {
+			l.Drawn = append(l.Drawn, value)
+		}
+
+		var remaining []int
+		for _, i := range pool {
+			if l.List[i].Value != value {
+				remaining = append(remaining, i)
+			}
+		}
+		pool = remaining
+	}
+
+	return picked
+}
+
+// pool returns the indexes of Items that can currently be picked at random.
+func (l *ListtoList) pool(exclude []string) []int {
+	var pool []int
+	for i, v := range l.List {
+		if !contains(exclude, v.Value) && (!l.Deck || !contains(l.Drawn, v.Value)) {
+			pool = append(pool, i)
+		}
+	}
+
+	return pool
+}
+
+// pickWeighted returns one of the given indexes, weighted by the Items' weights.
+func (l *ListtoList) pickWeighted(pool []int) int {
+	var total int
+	for _, i := range pool {
+		total += l.List[i].weight()
+	}
+
+	rng.Lock()
+	r := rng.Intn(total)
+	rng.Unlock()
+
+	for _, i := range pool {
+		r -= l.List[i].weight()
+		if r < 0 {
+			return i
+		}
+	}
+
+	return pool[len(pool)-1]
+}
+
+// SetWeight of an Item in the ListtoList.
+func (l *ListtoList) SetWeight(index, weight int) string {
+	if index < 0 || index >= len(l.List) || weight < 1 {
+		return ""
+	}
+
+	l.List[index].Weight = weight
+	if weight == 1 {
+		l.List[index].Weight = 0
+	}
+
+	return l.List[index].Value
+}
+
+// SetDeck turns deck mode on or off for the ListtoList, starting a fresh deck either way.
+func (l *ListtoList) SetDeck(deck bool) {
+	l.Deck = deck
+	l.Drawn = nil
 }
 
 // Sort a ListtoList by a value.
@@ -124,7 +506,51 @@ func (l *ListtoList) Sort(sorter string) {
 		sort.Slice(l.List, func(i, j int) bool {
 			return l.List[i].TimeAdded < l.List[j].TimeAdded
 		})
+	} else if sorter == "votes" {
+		l.List = l.Ranked()
+	}
+}
+
+// Ranked returns the Items in a ListtoList ordered by most votes, keeping the current order for ties.
+func (l *ListtoList) Ranked() []ListItem {
+	ranked := append([]ListItem(nil), l.List...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return len(ranked[i].Votes) > len(ranked[j].Votes)
+	})
+
+	return ranked
+}
+
+// Vote for an Item in the ListtoList. Each user can vote for each Item once.
+// Returns false if the user has already voted for the Item, or it doesn't exist.
+func (l *ListtoList) Vote(path []int, user string) bool {
+	item := l.Item(path)
+	if item == nil || contains(item.Votes, user) {
+		return false
 	}
+
+	item.Votes = append(item.Votes, user)
+
+	return true
+}
+
+// Unvote removes a user's vote for an Item in the ListtoList.
+// Returns false if the user hadn't voted for the Item, or it doesn't exist.
+func (l *ListtoList) Unvote(path []int, user string) bool {
+	item := l.Item(path)
+	if item == nil || !contains(item.Votes, user) {
+		return false
+	}
+
+	var votes []string
+	for _, v := range item.Votes {
+		if v != user {
+			votes = append(votes, v)
+		}
+	}
+	item.Votes = votes
+
+	return true
 }
 
 // AddAccess to certain perties to a private ListtoList.
@@ -152,9 +578,11 @@ func (l *ListtoList) AddAccess(access []string) {
 	}
 }
 
-func (l *ListtoList) RemoveAccess(access []string) {
-	if l.Type != PrivateList {
-		return
+// RemoveAccess takes users and roles off a private ListtoList, returning the ones that were removed.
+// The last entry is never removed, so somebody can always use the ListtoList.
+func (l *ListtoList) RemoveAccess(access []string) (removed []string) {
+	if l.Type != PrivateList && l.Type != RestrictedList {
+		return nil
 	}
 
 	for _, a := range access {
@@ -165,18 +593,26 @@ func (l *ListtoList) RemoveAccess(access []string) {
 			if a == v {
 				l.Access[i], l.Access[len(l.Access)-1] = l.Access[len(l.Access)-1], l.Access[i]
 				l.Access = l.Access[:len(l.Access)-1]
+				removed = append(removed, a)
 				break
 			}
 		}
 	}
+
+	return removed
 }
 
 // CanAccess returns if the caller can access the ListtoList.
 func (l *ListtoList) CanAccess(user string, roles []string) bool {
-	if l.Type == PublicList {
+	if l.Type == PublicList || l.Type == RestrictedList {
 		return true
 	}
 
+	return l.IsMember(user, roles)
+}
+
+// IsMember returns if a user, or one of their roles, has been given access to the ListtoList.
+func (l *ListtoList) IsMember(user string, roles []string) bool {
 	for _, a := range l.Access {
 		if a == user {
 			return true
@@ -191,3 +627,78 @@ func (l *ListtoList) CanAccess(user string, roles []string) bool {
 
 	return false
 }
+
+// CanEdit returns if a user can change the ListtoList, rather than only read it.
+// Nobody can change a locked ListtoList until it is unlocked.
+func (l *ListtoList) CanEdit(user string, roles []string) bool {
+	return !l.Locked && l.CanManage(user, roles)
+}
+
+// CanManage returns if a user could change the ListtoList when it isn't locked, so can lock and unlock it.
+// Anybody can change a public ListtoList, but only members can change a restricted one.
+func (l *ListtoList) CanManage(user string, roles []string) bool {
+	if contains(l.Viewers, user) {
+		return false
+	}
+
+	if l.Type == RestrictedList {
+		return l.IsMember(user, roles)
+	}
+
+	return l.CanAccess(user, roles)
+}
+
+// Share gives a user access to a personal ListtoList, either to read and write, or only to read.
+// Sharing again with the same user changes what they can do.
+func (l *ListtoList) Share(user string, write bool) {
+	if !contains(l.Access, user) {
+		l.Access = append(l.Access, user)
+	}
+
+	l.Viewers = remove(l.Viewers, user)
+	if !write {
+		l.Viewers = append(l.Viewers, user)
+	}
+}
+
+// Unshare takes away a user's access to a personal ListtoList, returning false if it wasn't shared with them.
+// The owner of the ListtoList can't be removed.
+func (l *ListtoList) Unshare(user string) bool {
+	if user == l.Guild || !contains(l.Access, user) {
+		return false
+	}
+
+	l.Access = remove(l.Access, user)
+	l.Viewers = remove(l.Viewers, user)
+
+	return true
+}
+
+// VisibleIn returns if the ListtoList can be seen from a channel, in the given category.
+// Lists that aren't bound to a channel or category are visible everywhere.
+func (l *ListtoList) VisibleIn(channel, category string) bool {
+	return l.Channel == "" || l.Channel == channel || l.Channel == category
+}
+
+// remove returns a slice without any copies of a value.
+func remove(values []string, value string) []string {
+	var kept []string
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+
+	return kept
+}
+
+// contains returns if a slice contains a value.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
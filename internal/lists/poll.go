@@ -0,0 +1,71 @@
+package lists
+
+import (
+	"sort"
+	"time"
+)
+
+// Poll is a timed vote on the Items in a ListtoList, held through reactions on a message.
+// Guild holds the user ID for personal lists, as with the ListtoList itself.
+type Poll struct {
+	Guild   string   `json:"guild"`
+	Name    string   `json:"name"`
+	Channel string   `json:"channel"`
+	Message string   `json:"message"`
+	Options []string `json:"options"`
+	Ends    int64    `json:"ends"`
+	Reorder bool     `json:"reorder,omitempty"`
+}
+
+// PollResult is how many votes an option in a Poll received.
+type PollResult struct {
+	Value string
+	Votes int
+}
+
+// Due returns if the Poll should be closed.
+func (p *Poll) Due(now time.Time) bool {
+	return now.Unix() >= p.Ends
+}
+
+// Results ranks the Poll's options by the votes given for each, keeping the list order for ties.
+func (p *Poll) Results(votes []int) []PollResult {
+	results := make([]PollResult, len(p.Options))
+	for i, o := range p.Options {
+		results[i].Value = o
+		if i < len(votes) {
+			results[i].Votes = votes[i]
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Votes > results[j].Votes
+	})
+
+	return results
+}
+
+// Reorder the Items in a ListtoList so those with the given values come first, in that order.
+// Any other Items follow in their current order.
+func (l *ListtoList) Reorder(values []string) {
+	items := make([]ListItem, 0, len(l.List))
+	used := make([]bool, len(l.List))
+
+	for _, v := range values {
+		for i, item := range l.List {
+			if !used[i] && item.Value == v {
+				items = append(items, item)
+				used[i] = true
+				break
+			}
+		}
+	}
+
+	for i, item := range l.List {
+		if !used[i] {
+			items = append(items, item)
+		}
+	}
+
+	l.List = items
+}
@@ -0,0 +1,144 @@
+package lists
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DarkieSouls/listto/internal/listtoErr"
+)
+
+const (
+	ClearAction    = "clear"
+	UncheckAction  = "uncheck"
+	TemplateAction = "template"
+)
+
+// Schedule resets a ListtoList on a recurring basis.
+// Guild holds the user ID for personal lists, as with the ListtoList itself.
+type Schedule struct {
+	Guild         string `json:"guild"`
+	Name          string `json:"name"`
+	Spec          string `json:"spec"`
+	Action        string `json:"action"`
+	Template      string `json:"template,omitempty"`
+	TemplateGuild string `json:"templateGuild,omitempty"`
+	Channel       string `json:"channel,omitempty"`
+	NextRun       int64  `json:"nextRun"`
+}
+
+// NewSchedule returns a new Schedule for a ListtoList, checking the spec is valid.
+// Specs take the form "daily 06:00", "weekly monday 06:00" or "monthly 1 06:00", optionally followed by a time zone such as Europe/London.
+func NewSchedule(guild, name, spec, action, template, channel string, now time.Time) (*Schedule, *listtoErr.ListtoError) {
+	s := &Schedule{
+		Guild:    guild,
+		Name:     name,
+		Spec:     spec,
+		Action:   action,
+		Template: template,
+		Channel:  channel,
+	}
+
+	next, err := s.Next(now)
+	if err != nil {
+		return nil, err
+	}
+	s.NextRun = next.Unix()
+
+	return s, nil
+}
+
+// Due returns if the Schedule should be run.
+func (s *Schedule) Due(now time.Time) bool {
+	return now.Unix() >= s.NextRun
+}
+
+// Next returns the next time the Schedule should run after the given time.
+func (s *Schedule) Next(after time.Time) (time.Time, *listtoErr.ListtoError) {
+	fields := strings.Fields(strings.ToLower(s.Spec))
+	if len(fields) < 2 {
+		return time.Time{}, listtoErr.InvalidScheduleError("a schedule needs a frequency and a time")
+	}
+
+	var day string
+	frequency := fields[0]
+	fields = fields[1:]
+	if frequency == "weekly" || frequency == "monthly" {
+		if len(fields) < 2 {
+			return time.Time{}, listtoErr.InvalidScheduleError(fmt.Sprintf("a %s schedule needs a day and a time", frequency))
+		}
+		day, fields = fields[0], fields[1:]
+	} else if frequency != "daily" {
+		return time.Time{}, listtoErr.InvalidScheduleError("schedules can only be daily, weekly or monthly")
+	}
+
+	clock, err := time.Parse("15:04", fields[0])
+	if err != nil {
+		return time.Time{}, listtoErr.InvalidScheduleError("times need to look like 06:00")
+	}
+
+	loc := time.UTC
+	if len(fields) > 1 {
+		// Zone names are case sensitive, so take them from the original spec.
+		original := strings.Fields(s.Spec)
+		loc, err = time.LoadLocation(original[len(original)-1])
+		if err != nil {
+			return time.Time{}, listtoErr.InvalidScheduleError(fmt.Sprintf("unknown time zone: %s", original[len(original)-1]))
+		}
+	}
+
+	after = after.In(loc)
+	next := time.Date(after.Year(), after.Month(), after.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+
+	switch frequency {
+	case "daily":
+		for !next.After(after) {
+			next = next.AddDate(0, 0, 1)
+		}
+	case "weekly":
+		weekday, ok := weekdays[day]
+		if !ok {
+			return time.Time{}, listtoErr.InvalidScheduleError(fmt.Sprintf("unknown day: %s", day))
+		}
+		for !next.After(after) || next.Weekday() != weekday {
+			next = next.AddDate(0, 0, 1)
+		}
+	case "monthly":
+		date, err := strconv.Atoi(day)
+		if err != nil || date < 1 || date > 28 {
+			return time.Time{}, listtoErr.InvalidScheduleError("monthly schedules need a day between 1 and 28")
+		}
+		next = time.Date(next.Year(), next.Month(), date, clock.Hour(), clock.Minute(), 0, 0, loc)
+		for !next.After(after) {
+			next = next.AddDate(0, 1, 0)
+		}
+	}
+
+	return next, nil
+}
+
+// Apply the Schedule's action to a ListtoList. Restoring from a template needs the Template.
+func (s *Schedule) Apply(l *ListtoList, t *Template, now time.Time) {
+	switch s.Action {
+	case ClearAction:
+		l.Clear()
+	case UncheckAction:
+		l.UncheckAll()
+	case TemplateAction:
+		if t != nil {
+			l.List = copyItems(t.List, "", now.Unix())
+		}
+	}
+}
+
+// weekdays maps day names to their time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
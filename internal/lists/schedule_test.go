@@ -0,0 +1,166 @@
+package lists
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleNext(t *testing.T) {
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name    string
+		spec    string
+		after   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "daily later the same day",
+			spec:  "daily 06:00",
+			after: "2021-03-10T05:00:00Z",
+			want:  "2021-03-10T06:00:00Z",
+		},
+		{
+			name:  "daily at the time itself moves to the next day",
+			spec:  "daily 06:00",
+			after: "2021-03-10T06:00:00Z",
+			want:  "2021-03-11T06:00:00Z",
+		},
+		{
+			name:  "daily ignores case",
+			spec:  "Daily 18:30",
+			after: "2021-03-10T19:00:00Z",
+			want:  "2021-03-11T18:30:00Z",
+		},
+		{
+			name:  "weekly later in the week",
+			spec:  "weekly monday 06:00",
+			after: "2021-03-10T12:00:00Z",
+			want:  "2021-03-15T06:00:00Z",
+		},
+		{
+			name:  "weekly later the same day",
+			spec:  "weekly wednesday 06:00",
+			after: "2021-03-10T05:00:00Z",
+			want:  "2021-03-10T06:00:00Z",
+		},
+		{
+			name:  "weekly after the time moves to the next week",
+			spec:  "weekly wednesday 06:00",
+			after: "2021-03-10T06:00:00Z",
+			want:  "2021-03-17T06:00:00Z",
+		},
+		{
+			name:  "monthly later in the month",
+			spec:  "monthly 15 06:00",
+			after: "2021-03-10T12:00:00Z",
+			want:  "2021-03-15T06:00:00Z",
+		},
+		{
+			name:  "monthly moves to the next month",
+			spec:  "monthly 1 06:00",
+			after: "2021-03-10T12:00:00Z",
+			want:  "2021-04-01T06:00:00Z",
+		},
+		{
+			name:  "monthly moves to the next year",
+			spec:  "monthly 1 06:00",
+			after: "2021-12-20T12:00:00Z",
+			want:  "2022-01-01T06:00:00Z",
+		},
+		{
+			name:  "daily across the clocks going forward in London",
+			spec:  "daily 06:00 Europe/London",
+			after: "2021-03-27T07:00:00Z",
+			want:  "2021-03-28T05:00:00Z",
+		},
+		{
+			name:  "daily across the clocks going back in London",
+			spec:  "daily 06:00 Europe/London",
+			after: "2021-10-30T12:00:00Z",
+			want:  "2021-10-31T06:00:00Z",
+		},
+		{
+			name:  "weekly across the clocks going forward in London",
+			spec:  "weekly sunday 06:00 Europe/London",
+			after: "2021-03-21T06:00:00Z",
+			want:  "2021-03-28T05:00:00Z",
+		},
+		{
+			name:    "empty",
+			spec:    "",
+			wantErr: true,
+		},
+		{
+			name:    "no time",
+			spec:    "daily",
+			wantErr: true,
+		},
+		{
+			name:    "unknown frequency",
+			spec:    "hourly 06:00",
+			wantErr: true,
+		},
+		{
+			name:    "weekly without a day",
+			spec:    "weekly 06:00",
+			wantErr: true,
+		},
+		{
+			name:    "unknown day",
+			spec:    "weekly someday 06:00",
+			wantErr: true,
+		},
+		{
+			name:    "badly formed time",
+			spec:    "daily 6am",
+			wantErr: true,
+		},
+		{
+			name:    "monthly day too late",
+			spec:    "monthly 29 06:00",
+			wantErr: true,
+		},
+		{
+			name:    "monthly day too early",
+			spec:    "monthly 0 06:00",
+			wantErr: true,
+		},
+		{
+			name:    "unknown time zone",
+			spec:    "daily 06:00 Mars/Olympus",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := at("2021-03-10T12:00:00Z")
+			if tt.after != "" {
+				after = at(tt.after)
+			}
+
+			s := &Schedule{Spec: tt.spec}
+			got, err := s.Next(after)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Next() = %s, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Next() error = %v", err)
+			}
+			if want := at(tt.want); !got.Equal(want) {
+				t.Errorf("Next() = %s, want %s", got.UTC(), want)
+			}
+		})
+	}
+}
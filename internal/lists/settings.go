@@ -0,0 +1,58 @@
+package lists
+
+// Settings holds the options for a guild, rather than for a single ListtoList.
+// For DMs the Guild is the user ID, as with personal lists, and Joined holds the owners
+// of personal lists that have been shared with the user, by list name.
+type Settings struct {
+	Guild    string            `json:"guild"`
+	Defaults map[string]string `json:"defaults,omitempty"`
+	Joined   map[string]string `json:"joined,omitempty"`
+
+	AdminRole    string `json:"adminRole,omitempty"`
+	PublicDelete bool   `json:"publicDelete,omitempty"`
+
+	Limits Limits `json:"limits"`
+}
+
+// NewSettings returns the Settings for a guild that hasn't changed anything.
+func NewSettings(guild string) *Settings {
+	return &Settings{Guild: guild}
+}
+
+// DefaultList returns the list used in a channel when no list is named, or "" if there isn't one.
+func (s *Settings) DefaultList(channel string) string {
+	return s.Defaults[channel]
+}
+
+// SetDefault sets the default list for a channel. An empty list removes the default.
+func (s *Settings) SetDefault(channel, list string) {
+	if list == "" {
+		delete(s.Defaults, channel)
+		return
+	}
+
+	if s.Defaults == nil {
+		s.Defaults = make(map[string]string)
+	}
+
+	s.Defaults[channel] = list
+}
+
+// Join records a personal list shared with the user, so it can be found by name.
+func (s *Settings) Join(list, owner string) {
+	if s.Joined == nil {
+		s.Joined = make(map[string]string)
+	}
+
+	s.Joined[list] = owner
+}
+
+// Leave forgets a personal list shared with the user.
+func (s *Settings) Leave(list string) {
+	delete(s.Joined, list)
+}
+
+// JoinedOwner returns who owns a shared list the user has joined, or "" if they haven't joined one with that name.
+func (s *Settings) JoinedOwner(list string) string {
+	return s.Joined[list]
+}
@@ -0,0 +1,44 @@
+package lists
+
+import "time"
+
+// shareCodeChars are the characters used in share codes, leaving out ones that are easy to mix up.
+const shareCodeChars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// shareLifetime is how long a share code can be used for.
+const shareLifetime = 24 * time.Hour
+
+// Share is an invite for a user to join a personal ListtoList, redeemed with its Code.
+type Share struct {
+	Code    string `json:"code"`
+	Owner   string `json:"owner"`
+	Name    string `json:"name"`
+	User    string `json:"user"`
+	Write   bool   `json:"write,omitempty"`
+	Expires int64  `json:"expires"`
+}
+
+// NewShare creates a Share for a user to join a personal ListtoList, with a new random code.
+func NewShare(owner, name, user string, write bool, now time.Time) *Share {
+	code := make([]byte, 8)
+
+	rng.Lock()
+	for i := range code {
+		code[i] = shareCodeChars[rng.Intn(len(shareCodeChars))]
+	}
+	rng.Unlock()
+
+	return &Share{
+		Code:    string(code),
+		Owner:   owner,
+		Name:    name,
+		User:    user,
+		Write:   write,
+		Expires: now.Add(shareLifetime).Unix(),
+	}
+}
+
+// Expired returns if the Share can no longer be used.
+func (s *Share) Expired(now time.Time) bool {
+	return now.Unix() >= s.Expires
+}
@@ -0,0 +1,72 @@
+package lists
+
+// Template is a stored set of items and access settings that new ListtoLists can be created from.
+// Guild holds the user ID for personal templates.
+type Template struct {
+	Guild   string     `json:"guild"`
+	Name    string     `json:"name"`
+	Creator string     `json:"creator"`
+	Type    ListType   `json:"type"`
+	Access  []string   `json:"access"`
+	List    []ListItem `json:"list"`
+}
+
+// NewTemplate returns a new Template holding a copy of a ListtoList.
+func NewTemplate(guild, name, creator string, l *ListtoList) *Template {
+	return &Template{
+		Guild:   guild,
+		Name:    name,
+		Creator: creator,
+		Type:    l.Type,
+		Access:  append([]string(nil), l.Access...),
+		List:    copyItems(l.List, "", 0),
+	}
+}
+
+// NewList returns a new ListtoList holding the Template's items.
+// Personal lists are only accessible by the user creating them, whatever the Template's settings.
+func (t *Template) NewList(guild, name, user string, personal bool, timeAdded int64) *ListtoList {
+	var l *ListtoList
+	switch {
+	case personal:
+		l = NewList(guild, name, PersonalList)
+		l.AddAccess([]string{user})
+	case t.Type == PrivateList || t.Type == RestrictedList:
+		l = NewList(guild, name, t.Type)
+		l.AddAccess(append(append([]string(nil), t.Access...), user))
+	default:
+		l = NewList(guild, name, PublicList)
+	}
+
+	l.List = copyItems(t.List, user, timeAdded)
+
+	return l
+}
+
+// copyItems deep copies a set of Items, marking them as added by the given user.
+func copyItems(items []ListItem, user string, timeAdded int64) []ListItem {
+	if items == nil {
+		return nil
+	}
+
+	copied := make([]ListItem, len(items))
+	for i, v := range items {
+		copied[i] = ListItem{
+			Value:     v.Value,
+			TimeAdded: timeAdded,
+			Weight:    v.Weight,
+			Assignee:  v.Assignee,
+			Tags:      append([]string(nil), v.Tags...),
+			Note:      v.Note,
+			AddedBy:   user,
+			Children:  copyItems(v.Children, user, timeAdded),
+		}
+
+		if v.Quantity != nil {
+			quantity := *v.Quantity
+			copied[i].Quantity = &quantity
+		}
+	}
+
+	return copied
+}
@@ -0,0 +1,211 @@
+package lists
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MaxDepth is how deeply Items can be nested. DynamoDB allows 32 levels of nesting in an item,
+// and every level of Items uses two of them.
+const MaxDepth = 10
+
+// ParsePath reads the position of an Item, which can be nested such as 1.2 for the third child of the second Item.
+// Returns false if the path isn't valid.
+func ParsePath(path string) ([]int, bool) {
+	if path == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(path, ".")
+	indexes := make([]int, len(parts))
+	for i, p := range parts {
+		index, err := strconv.Atoi(p)
+		if err != nil || index < 0 {
+			return nil, false
+		}
+		indexes[i] = index
+	}
+
+	return indexes, true
+}
+
+// FormatPath prints the position of an Item as read by ParsePath.
+func FormatPath(path []int) string {
+	parts := make([]string, len(path))
+	for i, p := range path {
+		parts[i] = strconv.Itoa(p)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Item returns the Item at a path in the ListtoList, or nil if there isn't one.
+func (l *ListtoList) Item(path []int) *ListItem {
+	items := l.List
+	var item *ListItem
+	for _, index := range path {
+		if index < 0 || index >= len(items) {
+			return nil
+		}
+		item = &items[index]
+		items = item.Children
+	}
+
+	return item
+}
+
+// siblings returns the Items that share a parent with the Item at a path, or nil if the parent doesn't exist.
+func (l *ListtoList) siblings(path []int) *[]ListItem {
+	if len(path) == 0 {
+		return nil
+	}
+
+	if len(path) == 1 {
+		return &l.List
+	}
+
+	parent := l.Item(path[:len(path)-1])
+	if parent == nil {
+		return nil
+	}
+
+	return &parent.Children
+}
+
+// EditPath changes the value of the Item at a path in the ListtoList.
+// Returns the old value, or an empty string if there is no Item there.
+func (l *ListtoList) EditPath(path []int, value, user string, timeEdited int64) string {
+	item := l.Item(path)
+	if item == nil {
+		return ""
+	}
+
+	name := item.Value
+	item.Value = value
+	item.EditedBy = user
+	item.TimeEdited = timeEdited
+
+	return name
+}
+
+// RemovePath removes the Item at a path in the ListtoList, along with its children.
+// Returns the removed value, or an empty string if there is no Item there.
+func (l *ListtoList) RemovePath(path []int) string {
+	items := l.siblings(path)
+	if items == nil {
+		return ""
+	}
+
+	index := path[len(path)-1]
+	if index < 0 || index >= len(*items) {
+		return ""
+	}
+
+	name := (*items)[index].Value
+	*items = append((*items)[:index], (*items)[index+1:]...)
+
+	return name
+}
+
+// AddChild Item under the Item at a path in the ListtoList.
+// Returns the parent's value, or an empty string if there is no Item there.
+// Items can't be added any deeper than MaxDepth.
+func (l *ListtoList) AddChild(path []int, item, user string, timeAdded int64) string {
+	if len(path) >= MaxDepth {
+		return ""
+	}
+
+	parent := l.Item(path)
+	if parent == nil {
+		return ""
+	}
+
+	parent.Children = append(parent.Children, ListItem{Value: item, TimeAdded: timeAdded, AddedBy: user})
+
+	return parent.Value
+}
+
+// SetDone marks the Item at a path in the ListtoList as done, or not done.
+// Returns the Item's value, or an empty string if there is no Item there.
+func (l *ListtoList) SetDone(path []int, done bool) string {
+	item := l.Item(path)
+	if item == nil {
+		return ""
+	}
+
+	item.Done = done
+
+	return item.Value
+}
+
+// MarkEditedPath records who last edited the Item at a path in the ListtoList, and when.
+func (l *ListtoList) MarkEditedPath(path []int, user string, timeEdited int64) {
+	item := l.Item(path)
+	if item == nil {
+		return
+	}
+
+	item.EditedBy = user
+	item.TimeEdited = timeEdited
+}
+
+// UncheckAll marks every Item in the ListtoList as not done, including nested Items.
+func (l *ListtoList) UncheckAll() {
+	uncheck(l.List)
+}
+
+// uncheck marks a set of Items and their children as not done.
+func uncheck(items []ListItem) {
+	for i := range items {
+		items[i].Done = false
+		uncheck(items[i].Children)
+	}
+}
+
+// CountDone returns how many Items in the ListtoList are done, and how many there are in total, including nested Items.
+func (l *ListtoList) CountDone() (done, total int) {
+	return countDone(l.List)
+}
+
+// Size returns how many Items the ListtoList holds, including nested Items.
+func (l *ListtoList) Size() int {
+	_, total := countDone(l.List)
+	return total
+}
+
+// LongestItem returns how many characters the longest Item in the ListtoList has, including nested Items.
+func (l *ListtoList) LongestItem() int {
+	return longestItem(l.List)
+}
+
+// longestItem finds the most characters in a set of Items and their children.
+func longestItem(items []ListItem) int {
+	var longest int
+	for _, v := range items {
+		if n := len([]rune(v.Value)); n > longest {
+			longest = n
+		}
+
+		if n := longestItem(v.Children); n > longest {
+			longest = n
+		}
+	}
+
+	return longest
+}
+
+// countDone counts the done Items in a set of Items and their children.
+func countDone(items []ListItem) (done, total int) {
+	for _, v := range items {
+		if v.Done {
+			done++
+		}
+		total++
+
+		d, t := countDone(v.Children)
+		done += d
+		total += t
+	}
+
+	return done, total
+}
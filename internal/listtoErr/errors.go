@@ -3,9 +3,17 @@ package listtoErr
 import "fmt"
 
 const (
-	Internal     = "InternalError"
-	InvalidVar   = "InvalidVariable"
-	ListNotFound = "ListNotFound"
+	Internal         = "InternalError"
+	Timeout          = "Timeout"
+	Unavailable      = "StorageUnavailable"
+	InvalidVar       = "InvalidVariable"
+	ListNotFound     = "ListNotFound"
+	ListExists       = "ListExists"
+	TemplateNotFound = "TemplateNotFound"
+	InvalidSchedule  = "InvalidSchedule"
+	ScheduleNotFound = "ScheduleNotFound"
+	PollNotFound     = "PollNotFound"
+	ShareNotFound    = "ShareNotFound"
 )
 
 // ListtoError is the type for error handling within Listto.
@@ -35,6 +43,22 @@ func ConvertError(err error) *ListtoError {
 	}
 }
 
+// TimeoutError returns an error when a request took too long, and was given up on.
+func TimeoutError(reason string) *ListtoError {
+	return &ListtoError{
+		Code:    Timeout,
+		Message: fmt.Sprintf("request timed out: %s", reason),
+	}
+}
+
+// StorageUnavailableError returns an error when storage isn't being called, as it has been failing.
+func StorageUnavailableError() *ListtoError {
+	return &ListtoError{
+		Code:    Unavailable,
+		Message: "storage is temporarily unavailable",
+	}
+}
+
 // InvalidEnvvar returns an error when an envvar is not as expected.
 func InvalidEnvvar(envvar string) *ListtoError {
 	return &ListtoError{
@@ -51,6 +75,14 @@ func ListNotFoundError(list string) *ListtoError {
 	}
 }
 
+// ListExistsError returns an error if a list couldn't be stored because another has the same name.
+func ListExistsError(list string) *ListtoError {
+	return &ListtoError{
+		Code:    ListExists,
+		Message: fmt.Sprintf("list already exists: %s", list),
+	}
+}
+
 // ListsNotFoundError returns an error if no lists could be found.
 func ListsNotFoundError() *ListtoError {
 	return &ListtoError{
@@ -59,6 +91,54 @@ func ListsNotFoundError() *ListtoError {
 	}
 }
 
+// TemplateNotFoundError returns an error if a template couldn't be found.
+func TemplateNotFoundError(template string) *ListtoError {
+	return &ListtoError{
+		Code:    TemplateNotFound,
+		Message: fmt.Sprintf("could not find template: %s", template),
+	}
+}
+
+// TemplatesNotFoundError returns an error if no templates could be found.
+func TemplatesNotFoundError() *ListtoError {
+	return &ListtoError{
+		Code:    TemplateNotFound,
+		Message: "could not find any templates",
+	}
+}
+
+// InvalidScheduleError returns an error explaining why a schedule isn't valid.
+func InvalidScheduleError(reason string) *ListtoError {
+	return &ListtoError{
+		Code:    InvalidSchedule,
+		Message: reason,
+	}
+}
+
+// ScheduleNotFoundError returns an error if a list has no schedule.
+func ScheduleNotFoundError(list string) *ListtoError {
+	return &ListtoError{
+		Code:    ScheduleNotFound,
+		Message: fmt.Sprintf("could not find a schedule for list: %s", list),
+	}
+}
+
+// PollNotFoundError returns an error if a list has no poll running.
+func PollNotFoundError(list string) *ListtoError {
+	return &ListtoError{
+		Code:    PollNotFound,
+		Message: fmt.Sprintf("could not find a poll for list: %s", list),
+	}
+}
+
+// ShareNotFoundError returns an error if a share code couldn't be found.
+func ShareNotFoundError(code string) *ListtoError {
+	return &ListtoError{
+		Code:    ShareNotFound,
+		Message: fmt.Sprintf("could not find share code: %s", code),
+	}
+}
+
 // LogError prints the error in bot logs.
 func (e *ListtoError) LogError() {
 	fmt.Println(fmt.Sprintf("%s: %s", e.CallingMethod, e.Message))